@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"net"
-	"os"
+	"strings"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
 	"github.com/spf13/cobra"
 )
 
@@ -14,43 +16,231 @@ const (
 		"cidr contains 10.0.0.0/16 10.0.14.5\n" +
 		"\n" +
 		"# Check whether an IPv6 CIDR range contains a given IPv6 address\n" +
-		"cidr contains 2001:db8:1234:1a00::/106 2001:db8:1234:1a00::"
+		"cidr contains 2001:db8:1234:1a00::/106 2001:db8:1234:1a00::\n" +
+		"\n" +
+		"# Check whether a CIDR range is fully contained in another CIDR range\n" +
+		"cidr contains 10.0.0.0/16 10.0.4.0/22\n" +
+		"\n" +
+		"# Check whether all given addresses and CIDR ranges are contained in a CIDR range\n" +
+		"cidr contains 10.0.0.0/16 10.0.14.5 10.0.4.0/22 10.1.0.1\n" +
+		"\n" +
+		"# Report which of the addresses in a file fall inside any of the networks in another file\n" +
+		"cidr contains --any --networks networks.txt --needles addresses.txt\n" +
+		"\n" +
+		"# Only use the exit code, 0 when the address is contained and 1 when it is not\n" +
+		"cidr contains --quiet 10.0.0.0/16 10.0.14.5"
 )
 
 var (
+	containsQuiet        bool
+	containsAny          bool
+	containsAll          bool
+	containsNetworksFile string
+	containsNeedlesFile  string
+
 	containsCmd = &cobra.Command{
-		Use:     "contains",
-		Short:   "Checks whether an IP address belongs to a CIDR range",
+		Use:   "contains [network] <address|network>...",
+		Short: "Checks whether IP addresses or CIDR ranges belong to a CIDR range",
+		Long: "Checks whether IP addresses or CIDR ranges (needles) belong to one or more CIDR ranges.\n" +
+			"The first argument is the CIDR range to check against, unless --networks is given.\n" +
+			"\n" +
+			"With a single CIDR range and needle, the result is printed as true, false or partial,\n" +
+			"where partial means that only part of a needle CIDR range is contained. Otherwise every\n" +
+			"needle is reported together with the CIDR ranges that contain it.\n" +
+			"\n" +
+			"Exits with 0 when all needles are fully contained (or any needle, with --any), and with 1 otherwise.",
 		Example: containsExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) != 2 {
-				fmt.Println("error: provide a CIDR range and an IP address")
-				fmt.Println("See 'cidr contains -h' for help and examples")
-				os.Exit(1)
-			}
-			network, err := core.ParseCIDR(args[0])
-			if err != nil {
-				fmt.Printf("error: %s\n", err)
-				fmt.Println("See 'cidr contains -h' for help and examples")
-				os.Exit(1)
-			}
-			ip := net.ParseIP(args[1])
-			if ip == nil {
-				fmt.Printf("error: invalid IP address: %s\n", args[1])
-				fmt.Println("See 'cidr contains -h' for help and examples")
-				os.Exit(1)
-			}
-			networkContainsIP := contains(network, ip)
-			fmt.Println(networkContainsIP)
-		},
+		RunE:    executeContains,
 	}
 )
 
 func init() {
 	rootCmd.AddCommand(containsCmd)
+	containsCmd.Flags().BoolVarP(&containsQuiet, "quiet", "q", false, "do not print the result, only set the exit code")
+	containsCmd.Flags().BoolVar(&containsAny, "any", false, "succeed when any needle is fully contained")
+	containsCmd.Flags().BoolVar(&containsAll, "all", false, "succeed only when all needles are fully contained (default)")
+	containsCmd.Flags().StringVar(&containsNetworksFile, "networks", "", "file with the CIDR ranges to check against, '-' for stdin")
+	containsCmd.Flags().StringVar(&containsNeedlesFile, "needles", "", "file with the addresses and CIDR ranges to check, '-' for stdin")
+	addInputFormatFlag(containsCmd.Flags())
+	addFormatFlag(containsCmd.Flags())
+	containsCmd.MarkFlagsMutuallyExclusive("any", "all")
 }
 
-func contains(network *net.IPNet, ip net.IP) bool {
-	contains := core.ContainsAddress(network, ip)
-	return contains
+// containsResult holds the CIDR ranges that contain a needle, fully or partially.
+type containsResult struct {
+	needle      input.Entry
+	containment core.Containment
+	networks    []*net.IPNet
+}
+
+func executeContains(cmd *cobra.Command, args []string) error {
+	networks, needles, err := containsArguments(args)
+	if err != nil {
+		return err
+	}
+
+	union := core.NewIPSet(networks...)
+	results := make([]containsResult, len(needles))
+	for i, needle := range needles {
+		results[i] = containedBy(networks, union, needle)
+	}
+
+	if templateFormat != "" && !containsQuiet {
+		if err := printContainsTemplate(results); err != nil {
+			return err
+		}
+	} else if !containsQuiet {
+		if len(networks) == 1 && len(needles) == 1 && containsNetworksFile == "" && containsNeedlesFile == "" {
+			printContainment(results[0].containment)
+		} else {
+			printContainsResults(results)
+		}
+	}
+
+	if !containsSucceeded(results) {
+		return errFalse
+	}
+	return nil
+}
+
+// containsSucceeded reports whether enough needles are fully contained for the mode of --any or --all,
+// where --all is the default.
+func containsSucceeded(results []containsResult) bool {
+	fullyContained := 0
+	for _, result := range results {
+		if result.containment == core.FullyContained {
+			fullyContained++
+		}
+	}
+	switch {
+	case containsAll:
+		return fullyContained == len(results)
+	case containsAny:
+		return fullyContained > 0
+	default:
+		return fullyContained == len(results)
+	}
+}
+
+// containsArguments collects the networks and needles from the arguments and files.
+func containsArguments(args []string) ([]*net.IPNet, []input.Entry, error) {
+	var networks []*net.IPNet
+	if containsNetworksFile != "" {
+		fileNetworks, err := readNetworks(containsNetworksFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		networks = fileNetworks
+	} else if len(args) > 0 {
+		network, err := parseNetwork(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		networks = []*net.IPNet{network}
+		args = args[1:]
+	}
+
+	var needles []input.Entry
+	for _, arg := range args {
+		network, err := parsePrefix(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		needles = append(needles, input.Entry{Network: network, Text: arg})
+	}
+	if containsNeedlesFile != "" {
+		entries, err := readEntries(containsNeedlesFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		needles = append(needles, entries...)
+	}
+
+	if len(networks) == 0 || len(needles) == 0 {
+		return nil, nil, usageError(errors.New("provide a CIDR range and an IP address or CIDR range"))
+	}
+	return networks, needles, nil
+}
+
+// containedBy returns the networks that fully contain the needle or, if there are none,
+// the networks that partially contain it. A needle that the partial networks cover together,
+// as the union of the networks, is fully contained in them.
+func containedBy(networks []*net.IPNet, union *core.IPSet, needle input.Entry) containsResult {
+	result := containsResult{needle: needle, containment: core.NotContained}
+	for _, network := range networks {
+		containment := core.GetContainment(network, needle.Network)
+		if containment == core.NotContained || containment < result.containment {
+			continue
+		}
+		if containment > result.containment {
+			result.containment = containment
+			result.networks = nil
+		}
+		result.networks = append(result.networks, network)
+	}
+	if result.containment == core.PartiallyContained && union.ContainsNetwork(needle.Network) {
+		result.containment = core.FullyContained
+	}
+	return result
+}
+
+func printContainment(containment core.Containment) {
+	switch containment {
+	case core.FullyContained:
+		fmt.Println(true)
+	case core.PartiallyContained:
+		fmt.Println("partial")
+	default:
+		fmt.Println(false)
+	}
+}
+
+func printContainsResults(results []containsResult) {
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.needle.Text))
+	}
+	for _, result := range results {
+		matches := make([]string, len(result.networks))
+		for i, network := range result.networks {
+			matches[i] = core.FormatNetwork(network)
+		}
+		switch result.containment {
+		case core.FullyContained:
+			fmt.Printf("%-*s    contained in %s\n", width, result.needle.Text, strings.Join(matches, ", "))
+		case core.PartiallyContained:
+			fmt.Printf("%-*s    partially in %s\n", width, result.needle.Text, strings.Join(matches, ", "))
+		default:
+			fmt.Printf("%-*s    not contained\n", width, result.needle.Text)
+		}
+	}
+}
+
+// printContainsTemplate prints every needle with the template set with --format.
+func printContainsTemplate(results []containsResult) error {
+	tmpl, err := parseTemplateFormat()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		data := &containsTemplateData{
+			Needle:      result.needle.Text,
+			Containment: "none",
+			Contained:   result.containment == core.FullyContained,
+			Networks:    make([]string, len(result.networks)),
+		}
+		switch result.containment {
+		case core.FullyContained:
+			data.Containment = "full"
+		case core.PartiallyContained:
+			data.Containment = "partial"
+		}
+		for i, network := range result.networks {
+			data.Networks[i] = core.FormatNetwork(network)
+		}
+		if err := executeTemplate(tmpl, data); err != nil {
+			return err
+		}
+	}
+	return nil
 }
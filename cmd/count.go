@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 	"net"
-	"os"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
 	"github.com/spf13/cobra"
@@ -15,7 +14,10 @@ const (
 		"cidr count 10.0.0.0/16\n" +
 		"\n" +
 		"# Return the count of all addresses within a given IPv6 CIDR range\n" +
-		"cidr count 2001:db8:1234:1a00::/106"
+		"cidr count 2001:db8:1234:1a00::/106\n" +
+		"\n" +
+		"# Return the count with thousands separators\n" +
+		"cidr count --format '{{formatNumber .Addresses}}' 10.0.0.0/8"
 )
 
 var (
@@ -23,27 +25,29 @@ var (
 		Use:     "count",
 		Short:   "Return the count of all addresses in a given CIDR range",
 		Example: countExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) != 1 {
-				fmt.Println("error: provide a CIDR range")
-				fmt.Println("See 'cidr count -h' for help and examples")
-				os.Exit(1)
-			}
-			network, err := core.ParseCIDR(args[0])
+		Args:    usageArgs("provide a CIDR range", cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := parseNetwork(args[0])
 			if err != nil {
-				fmt.Printf("error: invalid CIDR range: %s\n", args[0])
-				fmt.Println("See 'cidr count -h' for help and examples")
-				os.Exit(1)
+				return err
 			}
 			hostAddressCount := count(network)
+			if templateFormat != "" {
+				tmpl, err := parseTemplateFormat()
+				if err != nil {
+					return err
+				}
+				return executeTemplate(tmpl, &countTemplateData{Network: core.FormatNetwork(network), Addresses: hostAddressCount})
+			}
 			fmt.Println(hostAddressCount)
-
+			return nil
 		},
 	}
 )
 
 func init() {
 	rootCmd.AddCommand(countCmd)
+	addFormatFlag(countCmd.Flags())
 }
 
 func count(network *net.IPNet) *big.Int {
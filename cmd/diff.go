@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffExample = "# Compare the address space of two lists of CIDR ranges\n" +
+		"$ cidr diff old.txt new.txt\n" +
+		"  10.0.0.0/16    65,536\n" +
+		"- 10.1.0.0/16    65,536\n" +
+		"+ 10.2.0.0/16    65,536\n" +
+		"\n" +
+		"Added:          65,536 IPv4 addresses\n" +
+		"Removed:        65,536 IPv4 addresses\n" +
+		"Unchanged:      65,536 IPv4 addresses\n" +
+		"\n" +
+		"# Read the old list from stdin and report the differences as JSON\n" +
+		"cidr diff --output json - new.txt < old.txt"
+)
+
+var (
+	diffOutput string
+
+	diffCmd = &cobra.Command{
+		Use:   "diff <old-file> <new-file>",
+		Short: "Compares the address space of two lists of CIDR ranges",
+		Long: "Compares the address space of two lists of CIDR ranges, one CIDR range or IP address per line.\n" +
+			"Both lists are normalised and aggregated first, so only the address space that was added or removed\n" +
+			"is reported. Exits with 1 when the lists differ, which makes it usable as a check in CI.",
+		Example: diffExample,
+		Args:    usageArgs("provide 2 files with CIDR ranges", cobra.ExactArgs(2)),
+		RunE:    executeDiff,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "text", "output format, one of text or json")
+	addInputFormatFlag(diffCmd.Flags())
+}
+
+// diffEntry is a CIDR range of address space that was added, removed or kept.
+type diffEntry struct {
+	Network   string `json:"network"`
+	Addresses string `json:"addresses"`
+}
+
+// diffCount holds the number of addresses per address family.
+type diffCount struct {
+	IPv4 string `json:"ipv4"`
+	IPv6 string `json:"ipv6"`
+}
+
+type diffResult struct {
+	Added              []diffEntry `json:"added"`
+	Removed            []diffEntry `json:"removed"`
+	Unchanged          []diffEntry `json:"unchanged"`
+	AddedAddresses     diffCount   `json:"addedAddresses"`
+	RemovedAddresses   diffCount   `json:"removedAddresses"`
+	UnchangedAddresses diffCount   `json:"unchangedAddresses"`
+}
+
+func executeDiff(cmd *cobra.Command, args []string) error {
+	if diffOutput != "text" && diffOutput != "json" {
+		return usageError(fmt.Errorf("unknown output format: %s", diffOutput))
+	}
+
+	oldNetworks, err := readNetworks(args[0])
+	if err != nil {
+		return err
+	}
+	newNetworks, err := readNetworks(args[1])
+	if err != nil {
+		return err
+	}
+
+	oldSet, newSet := core.NewIPSet(oldNetworks...), core.NewIPSet(newNetworks...)
+	added := newSet.Difference(oldSet)
+	removed := oldSet.Difference(newSet)
+	unchanged := oldSet.Intersection(newSet)
+
+	if diffOutput == "json" {
+		if err := printDiffJSON(added, removed, unchanged); err != nil {
+			return err
+		}
+	} else {
+		printDiff(added, removed, unchanged)
+	}
+
+	if !added.IsEmpty() || !removed.IsEmpty() {
+		return errFalse
+	}
+	return nil
+}
+
+func printDiff(added, removed, unchanged *core.IPSet) {
+	type line struct {
+		marker  string
+		network *net.IPNet
+		print   func(format string, a ...interface{}) string
+	}
+	var lines []line
+	for _, network := range added.Networks() {
+		lines = append(lines, line{"+", network, color.GreenString})
+	}
+	for _, network := range removed.Networks() {
+		lines = append(lines, line{"-", network, color.RedString})
+	}
+	for _, network := range unchanged.Networks() {
+		lines = append(lines, line{" ", network, fmt.Sprintf})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return core.CompareNetworks(lines[i].network, lines[j].network) < 0
+	})
+
+	width := 0
+	for _, l := range lines {
+		width = max(width, len(core.FormatNetwork(l.network)))
+	}
+	for _, l := range lines {
+		fmt.Println(l.print("%s %-*s    %s", l.marker, width, core.FormatNetwork(l.network), formatCount(core.GetAddressCount(l.network))))
+	}
+	if len(lines) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf(color.BlueString("Added:\t\t")+"%s\n", formatSetCount(added))
+	fmt.Printf(color.BlueString("Removed:\t")+"%s\n", formatSetCount(removed))
+	fmt.Printf(color.BlueString("Unchanged:\t")+"%s\n", formatSetCount(unchanged))
+}
+
+func printDiffJSON(added, removed, unchanged *core.IPSet) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diffResult{
+		Added:              diffEntries(added),
+		Removed:            diffEntries(removed),
+		Unchanged:          diffEntries(unchanged),
+		AddedAddresses:     diffCounts(added),
+		RemovedAddresses:   diffCounts(removed),
+		UnchangedAddresses: diffCounts(unchanged),
+	})
+}
+
+func diffEntries(set *core.IPSet) []diffEntry {
+	entries := make([]diffEntry, 0)
+	for _, network := range set.Networks() {
+		entries = append(entries, diffEntry{Network: core.FormatNetwork(network), Addresses: core.GetAddressCount(network).String()})
+	}
+	return entries
+}
+
+func diffCounts(set *core.IPSet) diffCount {
+	return diffCount{
+		IPv4: set.IPv4().GetAddressCount().String(),
+		IPv6: set.IPv6().GetAddressCount().String(),
+	}
+}
+
+// formatCount formats an address count as a human-readable number.
+func formatCount(count *big.Int) string {
+	return helper.FormatNumber(count.String())
+}
+
+// formatSetCount formats the number of addresses in a set per address family.
+func formatSetCount(set *core.IPSet) string {
+	var parts []string
+	if ipv4 := set.IPv4(); !ipv4.IsEmpty() {
+		parts = append(parts, formatCount(ipv4.GetAddressCount())+" IPv4 addresses")
+	}
+	if ipv6 := set.IPv6(); !ipv6.IsEmpty() {
+		parts = append(parts, formatCount(ipv6.GetAddressCount())+" IPv6 addresses")
+	}
+	if len(parts) == 0 {
+		return "0 addresses"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"os"
 	"strconv"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
 	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/bschaatsbergen/cidr/internal/table"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -22,13 +24,21 @@ const (
 		"10.0.80.0/20\n" +
 		"10.0.96.0/20\n" +
 		"10.0.112.0/20\n" +
-		"10.0.128.0/20\n"
+		"10.0.128.0/20\n" +
+		"\n" +
+		"# Print every network with its usable range, broadcast address and size, for a design doc\n" +
+		"$ cidr divide --table=markdown 10.0.0.0/16 4\n" +
+		"\n" +
+		"# Print the first usable address of every network\n" +
+		"$ cidr divide --format '{{.FirstUsable}}' 10.0.0.0/16 4"
 )
 
+var divideTable string
+
 var divideCmd = &cobra.Command{
 	Use:     "divide",
 	Short:   "Divides the given CIDR range into N distinct networks",
-	Args:    cobra.MinimumNArgs(2),
+	Args:    usageArgs("provide a CIDR range and a divisor", cobra.MinimumNArgs(2)),
 	Example: divideExample,
 	PreRunE: validateDivideArguments,
 	RunE:    executeDivide,
@@ -36,64 +46,107 @@ var divideCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(divideCmd)
+	divideCmd.Flags().StringVar(&divideTable, "table", "", "print every network as a table, one of text, markdown or csv")
+	divideCmd.Flags().Lookup("table").NoOptDefVal = "text"
+	addFormatFlag(divideCmd.Flags())
+	divideCmd.MarkFlagsMutuallyExclusive("table", "format")
 }
 
 func validateDivideArguments(cmd *cobra.Command, args []string) error {
 	// Ensure CIDR is valid
-	_, _, err := net.ParseCIDR(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid network: %s", args[0])
+	if _, err := parseNetwork(args[0]); err != nil {
+		return err
 	}
 
 	// Ensure divisor is a valid integer
-	_, err = strconv.ParseInt(args[1], 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid divisor: %s", args[1])
+	if _, err := parseDivisor(args[1]); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func executeDivide(cmd *cobra.Command, args []string) error {
-	network, err := core.ParseCIDR(args[0])
+	network, err := parseNetwork(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid network: %s", args[0])
+		return err
 	}
 
 	maskSize, _ := network.Mask.Size()
 	if (helper.IsIPv4Network(network) && maskSize == 32) || maskSize >= 128 {
-		return fmt.Errorf("invalid network mask size: %s", args[0])
+		return inputError(fmt.Errorf("invalid network mask size: %s", args[0]))
 	}
 
-	divisor, err := strconv.ParseInt(args[1], 10, 64)
+	divisor, err := parseDivisor(args[1])
 	if err != nil {
-		return fmt.Errorf("invalid divisor: %s", args[1])
+		return err
+	}
+	if err := validateTableFormat(divideTable); err != nil {
+		return err
 	}
 
 	networks, err := core.DivideCIDR(network, divisor)
 	if err != nil {
-		return err
+		return inputError(err)
 	}
 
+	if divideTable != "" {
+		return printNetworkTable(networks, divideTable)
+	}
+	if templateFormat != "" {
+		tmpl, err := parseTemplateFormat()
+		if err != nil {
+			return err
+		}
+		for _, network := range networks {
+			if err := executeTemplate(tmpl, getNetworkTemplateData(&network)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	printNetworkPartitions(networks)
 	return nil
 }
 
+// parseDivisor parses a divisor argument, reporting failures as invalid input.
+func parseDivisor(arg string) (int64, error) {
+	divisor, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, inputError(fmt.Errorf("%w: %s", core.ErrInvalidDivisor, arg))
+	}
+	return divisor, nil
+}
+
 func printNetworkPartitions(networks []net.IPNet) {
 	const truncateLimit = 50
 	networkCount := len(networks)
 
 	if networkCount <= truncateLimit {
 		for _, network := range networks {
-			fmt.Println(network.String())
+			fmt.Println(core.FormatNetwork(&network))
 		}
 	} else {
 		for i := 0; i < truncateLimit/2; i++ {
-			fmt.Println(networks[i].String())
+			fmt.Println(core.FormatNetwork(&networks[i]))
 		}
 		fmt.Println(color.BlueString("......"))
 		for i := networkCount - truncateLimit/2; i < networkCount; i++ {
-			fmt.Println(networks[i].String())
+			fmt.Println(core.FormatNetwork(&networks[i]))
 		}
 	}
 }
+
+// validateTableFormat returns a usage error if the format of the --table flag is set but unknown.
+func validateTableFormat(format string) error {
+	if format != "" && format != table.FormatText && format != table.FormatMarkdown && format != table.FormatCSV {
+		return usageError(fmt.Errorf("unknown table format: %s", format))
+	}
+	return nil
+}
+
+// printNetworkTable prints every network with the details of explain as a table in the given format.
+func printNetworkTable(networks []net.IPNet, format string) error {
+	// Leave the thousands separators out of CSV, so that spreadsheets read the counts as numbers.
+	return table.Networks(networks, format != table.FormatCSV).Write(os.Stdout, format)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes that make up the contract scripts can rely on.
+const (
+	// exitTrue is returned when a command succeeds, or when a check holds.
+	exitTrue = 0
+	// exitFalse is returned when a check such as contains or overlaps does not hold.
+	exitFalse = 1
+	// exitUsage is returned when a command is invoked with the wrong arguments or flags.
+	exitUsage = 2
+	// exitInvalidInput is returned when an argument or input could not be parsed or processed.
+	exitInvalidInput = 3
+)
+
+// exitError carries the exit code that the process should terminate with.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// errFalse is returned by checks that do not hold, it is never printed.
+var errFalse = &exitError{code: exitFalse, err: errors.New("false")}
+
+// usageError marks err as caused by the wrong usage of a command.
+func usageError(err error) error {
+	return &exitError{code: exitUsage, err: err}
+}
+
+// inputError marks err as caused by input that could not be parsed or processed.
+func inputError(err error) error {
+	return &exitError{code: exitInvalidInput, err: err}
+}
+
+// usageArgs wraps a positional argument validator so that a failed validation is reported
+// as a usage error with the given message.
+func usageArgs(msg string, validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return usageError(errors.New(msg))
+		}
+		return nil
+	}
+}
+
+// parseNetwork parses a CIDR range argument, reporting failures as invalid input.
+func parseNetwork(arg string) (*net.IPNet, error) {
+	network, err := core.ParseCIDR(arg)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	return unmapNetwork(network), nil
+}
+
+// parseAddress parses an IP address argument, reporting failures as invalid input.
+func parseAddress(arg string) (net.IP, error) {
+	ip, err := core.ParseAddress(arg)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	if unmap {
+		ip = core.UnmapAddress(ip)
+	}
+	return ip, nil
+}
+
+// unmapNetwork returns the IPv4 network that an IPv4-mapped network stands for if --unmap is set.
+func unmapNetwork(network *net.IPNet) *net.IPNet {
+	if unmap {
+		return core.Unmap(network)
+	}
+	return network
+}
+
+// handleError prints err to stderr and returns the exit code that belongs to it.
+// Errors that are not explicitly marked originate from cobra and are treated as usage errors.
+func handleError(cmd *cobra.Command, err error) int {
+	code := exitUsage
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.code
+	}
+	if code == exitFalse {
+		return code
+	}
+
+	fmt.Fprintf(os.Stderr, "error: %s\n", err)
+	if code == exitUsage && cmd != nil {
+		fmt.Fprintf(os.Stderr, "See '%s -h' for help and examples\n", cmd.CommandPath())
+	}
+	return code
+}
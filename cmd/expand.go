@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/spf13/cobra"
+)
+
+const (
+	expandExample = "# Turn a scan scope into CIDR ranges\n" +
+		"cidr expand 10.0.1-5.0/24 192.168.*.1 10.0.0.1,3,7-9\n" +
+		"\n" +
+		"# Print every address in scope, leaving out the ranges that must not be scanned\n" +
+		"cidr expand --addresses --exclude out-of-scope.txt 10.0.0.0/24 10.0.1.10-10.0.1.20\n" +
+		"\n" +
+		"# Read target specifications from a file as input of any command that reads lists\n" +
+		"cidr set union --input-format nmap scope.txt"
+)
+
+// maxExpandAddresses limits the number of addresses that are printed with --addresses.
+const maxExpandAddresses = 1 << 24
+
+var (
+	expandAddresses   bool
+	expandExcludeFile string
+
+	expandCmd = &cobra.Command{
+		Use:   "expand <target>...",
+		Short: "Expands nmap-style target specifications into CIDR ranges or addresses",
+		Long: "Expands target specifications in the style of nmap into the smallest list of CIDR ranges that covers\n" +
+			"them, IPv4 before IPv6. Next to CIDR ranges and addresses, a target is an IPv4 address of which every\n" +
+			"octet is a list of values and ranges, such as 10.0.0.1,3,7-9, with * for all values, such as 192.168.*.1,\n" +
+			"optionally followed by a prefix length, such as 10.0.1-5.0/24, or a range of addresses, such as\n" +
+			"10.0.0.1-10.0.0.20. Use --input-format nmap to read lists of targets in other commands.",
+		Example: expandExample,
+		Args:    usageArgs("provide at least 1 target", cobra.MinimumNArgs(1)),
+		RunE:    executeExpand,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(expandCmd)
+	expandCmd.Flags().BoolVar(&expandAddresses, "addresses", false, "print every address instead of CIDR ranges")
+	expandCmd.Flags().StringVar(&expandExcludeFile, "exclude", "", "file with CIDR ranges and addresses to leave out, '-' for stdin")
+	addInputFormatFlag(expandCmd.Flags())
+}
+
+func executeExpand(cmd *cobra.Command, args []string) error {
+	var networks []*net.IPNet
+	for _, arg := range args {
+		targets, err := input.ParseTarget(arg)
+		if err != nil {
+			return inputError(err)
+		}
+		for _, target := range targets {
+			networks = append(networks, unmapNetwork(target))
+		}
+	}
+	set := core.NewIPSet(networks...)
+	if expandExcludeFile != "" {
+		exclusions, err := readNetworks(expandExcludeFile)
+		if err != nil {
+			return err
+		}
+		set = set.Difference(core.NewIPSet(exclusions...))
+	}
+
+	if !expandAddresses {
+		for _, network := range set.Networks() {
+			fmt.Println(core.FormatNetwork(network))
+		}
+		return nil
+	}
+
+	if count := set.GetAddressCount(); count.Cmp(big.NewInt(maxExpandAddresses)) > 0 {
+		return usageError(fmt.Errorf("the targets hold %s addresses, more than %d, leave out --addresses to print CIDR ranges", count, maxExpandAddresses))
+	}
+	one := big.NewInt(1)
+	for _, r := range set.Ranges() {
+		last := core.AddressToInt(r.Last)
+		for i := core.AddressToInt(r.First); i.Cmp(last) <= 0; i.Add(i, one) {
+			ip, err := core.IntToAddress(i, len(r.First) == net.IPv4len)
+			if err != nil {
+				return err
+			}
+			fmt.Println(core.FormatAddress(ip))
+		}
+	}
+	return nil
+}
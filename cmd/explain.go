@@ -3,10 +3,8 @@ package cmd
 import (
 	"fmt"
 	"net"
-	"os"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
-	"github.com/bschaatsbergen/cidr/internal/helper"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -16,116 +14,61 @@ const (
 		"cidr explain 10.1.0.0/16\n" +
 		"\n" +
 		"# Explain the details of a given IPv6 CIDR range\n" +
-		"cidr explain 2001:db8:1234:1a00::/106"
+		"cidr explain 2001:db8:1234:1a00::/106\n" +
+		"\n" +
+		"# Print only the base and broadcast address\n" +
+		"cidr explain --format '{{.BaseAddress}} {{.Broadcast}}' 10.1.0.0/16\n" +
+		"\n" +
+		"# Print the details as a markdown table, for a design doc\n" +
+		"cidr explain --table=markdown 10.1.0.0/16"
 )
 
 var (
+	explainTable string
+
 	explainCmd = &cobra.Command{
 		Use:     "explain",
 		Short:   "Provides information about a CIDR range",
 		Example: explainExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) != 1 {
-				fmt.Println("error: provide a CIDR range")
-				fmt.Println("See 'cidr contains -h' for help and examples")
-				os.Exit(1)
-			}
-			network, err := core.ParseCIDR(args[0])
+		Args:    usageArgs("provide a CIDR range", cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := parseNetwork(args[0])
 			if err != nil {
-				fmt.Printf("error: %s\n", err)
-				fmt.Println("See 'cidr contains -h' for help and examples")
-				os.Exit(1)
+				return err
+			}
+			if err := validateTableFormat(explainTable); err != nil {
+				return err
+			}
+			if explainTable != "" {
+				return printNetworkTable([]net.IPNet{*network}, explainTable)
+			}
+			if templateFormat != "" {
+				tmpl, err := parseTemplateFormat()
+				if err != nil {
+					return err
+				}
+				return executeTemplate(tmpl, getNetworkTemplateData(network))
 			}
-			details := getNetworkDetails(network)
+			details := core.GetNetworkDetails(network)
 			explain(details)
+			return nil
 		},
 	}
 )
 
 func init() {
 	rootCmd.AddCommand(explainCmd)
-}
-
-type networkDetailsToDisplay struct {
-	IsIPV4Network              bool
-	IsIPV6Network              bool
-	BroadcastAddress           string
-	BroadcastAddressHasError   bool
-	Netmask                    net.IP
-	PrefixLength               int
-	BaseAddress                net.IP
-	Count                      string
-	HostCount                  string
-	UsableAddressRangeHasError bool
-	FirstUsableIPAddress       string
-	LastUsableIPAddress        string
-}
-
-func getNetworkDetails(network *net.IPNet) *networkDetailsToDisplay {
-	details := &networkDetailsToDisplay{}
-
-	// Determine whether the network is an IPv4 or IPv6 network.
-	if helper.IsIPv4Network(network) {
-		details.IsIPV4Network = true
-	} else if helper.IsIPv6Network(network) {
-		details.IsIPV6Network = true
-	}
-
-	// Obtain the broadcast address, handling errors if they occur.
-	ipBroadcast, err := core.GetBroadcastAddress(network)
-	if err != nil {
-		// Set error flags and store the error message so that it can be displayed later.
-		details.BroadcastAddressHasError = true
-		details.BroadcastAddress = err.Error()
-	} else {
-		details.BroadcastAddress = ipBroadcast.String()
-	}
-
-	// Obtain the netmask and prefix length.
-	netmask := core.GetNetmask(network)
-	// A human-readable representation of the netmask is displayed in the output.
-	details.Netmask = core.NetMaskToIPAddress(netmask)
-	details.PrefixLength = core.GetPrefixLength(details.Netmask)
-
-	// Obtain the base address of the network.
-	details.BaseAddress = core.GetBaseAddress(network)
-
-	// Obtain the total count of addresses in the network.
-	count := core.GetAddressCount(network)
-	// Format the count as a human-readable string and store it in the details struct.
-	details.Count = helper.FormatNumber(count.String())
-
-	// Obtain the total count of distinct host addresses in the network.
-	hostCount := core.GetHostAddressCount(network)
-	// Format the count as a human-readable string and store it in the details struct.
-	details.HostCount = helper.FormatNumber(hostCount.String())
-
-	// Obtain the first and last usable IP addresses, handling errors if they occur.
-	firstUsableIP, err := core.GetFirstUsableIPAddress(network)
-	if err != nil {
-		// Set error flags if an error occurs during the retrieval of the first usable IP address.
-		details.UsableAddressRangeHasError = true
-	} else {
-		details.FirstUsableIPAddress = firstUsableIP.String()
-	}
-
-	lastUsableIP, err := core.GetLastUsableIPAddress(network)
-	if err != nil {
-		// Set error flags if an error occurs during the retrieval of the last usable IP address.
-		details.UsableAddressRangeHasError = true
-	} else {
-		details.LastUsableIPAddress = lastUsableIP.String()
-	}
-
-	// Return the populated 'networkDetailsToDisplay' struct.
-	return details
+	explainCmd.Flags().StringVar(&explainTable, "table", "", "print the details as a table, one of text, markdown or csv")
+	explainCmd.Flags().Lookup("table").NoOptDefVal = "text"
+	addFormatFlag(explainCmd.Flags())
+	explainCmd.MarkFlagsMutuallyExclusive("table", "format")
 }
 
 //nolint:goconst
-func explain(details *networkDetailsToDisplay) {
+func explain(details *core.NetworkDetails) {
 	var lengthIndicator string
 
-	fmt.Printf(color.BlueString("Base Address:\t\t ")+"%s\n", details.BaseAddress)
+	fmt.Printf(color.BlueString("Base Address:\t\t ")+"%s\n", core.FormatAddress(details.BaseAddress))
 
 	if !details.UsableAddressRangeHasError {
 		fmt.Printf(color.BlueString("Usable Address Range:\t ")+"%s to %s (%s)\n", details.FirstUsableIPAddress, details.LastUsableIPAddress, details.HostCount)
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/export"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/spf13/cobra"
+)
+
+const (
+	exportExample = "# Turn a list of CIDR ranges into an ipset restore file\n" +
+		"cidr export --format ipset --file office.txt | ipset restore\n" +
+		"\n" +
+		"# Aggregate the ranges from stdin into a Cisco prefix-list that denies them\n" +
+		"cat blocked.txt | cidr export --format cisco --aggregate --action deny --name BLOCKED\n" +
+		"\n" +
+		"# Allow a few ranges in nginx, with a comment per range\n" +
+		"cidr export --format nginx 10.0.0.0/8=corp 2001:db8::/32=documentation"
+)
+
+var (
+	exportFormat    string
+	exportFile      string
+	exportAggregate bool
+	exportName      string
+	exportAction    string
+
+	exportCmd = &cobra.Command{
+		Use:   "export --format <target> [prefix[=label]...]",
+		Short: "Exports a list of CIDR ranges as a firewall or ACL configuration",
+		Long: "Exports a list of CIDR ranges as a firewall or ACL configuration. The ranges are taken from the\n" +
+			"arguments and the --file, or from stdin when neither is given. IPv4 ranges are written before IPv6\n" +
+			"ranges, and targets that need a set or list per address family get one for each.\n" +
+			"\n" +
+			"Formats:\n" + exportFormatsHelp(),
+		Example: exportExample,
+		RunE:    executeExport,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "target to export to, see the list of formats above")
+	exportCmd.Flags().StringVar(&exportFile, "file", "", "file with CIDR ranges and their labels, '-' for stdin")
+	exportCmd.Flags().BoolVar(&exportAggregate, "aggregate", false, "aggregate the ranges into the smallest list that covers them, dropping labels")
+	exportCmd.Flags().StringVar(&exportName, "name", "cidr", "name of the generated set, table, chain or list")
+	exportCmd.Flags().StringVar(&exportAction, "action", export.ActionAllow, "whether to allow or deny the ranges, for the formats that support both")
+	_ = exportCmd.MarkFlagRequired("format")
+	addInputFormatFlag(exportCmd.Flags())
+}
+
+func executeExport(cmd *cobra.Command, args []string) error {
+	file := exportFile
+	if len(args) == 0 && file == "" {
+		file = input.Stdin
+	}
+	entries, err := readLabelledNetworks(args, file)
+	if err != nil {
+		return err
+	}
+	if exportAggregate {
+		entries = export.Aggregate(entries)
+	}
+
+	err = export.Write(os.Stdout, exportFormat, entries, export.Options{Name: exportName, Action: exportAction})
+	if errors.Is(err, export.ErrUnknownFormat) || errors.Is(err, export.ErrUnknownAction) {
+		return usageError(err)
+	}
+	return err
+}
+
+// exportFormatsHelp lists the export formats with their description.
+func exportFormatsHelp() string {
+	var b strings.Builder
+	for _, format := range export.Formats() {
+		fmt.Fprintf(&b, "  %-20s %s\n", format.Name, format.Description)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
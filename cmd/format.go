@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/spf13/pflag"
+)
+
+// templateFormat is the Go template set with --format, empty for the regular output.
+var templateFormat string
+
+// addFormatFlag adds the --format flag to the flags of a command that supports Go templates.
+func addFormatFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&templateFormat, "format", "", "print the result using a Go template, such as '{{.BaseAddress}}'")
+}
+
+// templateFuncs are the functions that are available in --format templates, next to the
+// builtin functions of text/template.
+var templateFuncs = template.FuncMap{
+	// formatNumber adds thousands separators to a number, such as an address count.
+	"formatNumber": func(v any) string {
+		return helper.FormatNumber(fmt.Sprint(v))
+	},
+	// netmask returns the netmask of a CIDR range, such as 255.255.0.0 for 10.0.0.0/16.
+	"netmask": func(network string) (string, error) {
+		n, err := core.ParseCIDR(network)
+		if err != nil {
+			return "", err
+		}
+		return core.FormatAddress(core.NetMaskToIPAddress(n.Mask)), nil
+	},
+	// hostmask returns the inverse of the netmask of a CIDR range, such as 0.0.255.255 for 10.0.0.0/16.
+	"hostmask": func(network string) (string, error) {
+		n, err := core.ParseCIDR(network)
+		if err != nil {
+			return "", err
+		}
+		hostmask := make(net.IP, len(n.Mask))
+		for i, b := range n.Mask {
+			hostmask[i] = ^b
+		}
+		return core.FormatAddress(hostmask), nil
+	},
+	// family returns the address family of a CIDR range or IP address, IPv4 or IPv6.
+	"family": func(s string) (string, error) {
+		n, err := core.ParseCIDR(s)
+		if err != nil {
+			ip, err := core.ParseAddress(s)
+			if err != nil {
+				return "", err
+			}
+			n = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		if helper.IsIPv4Network(n) {
+			return "IPv4", nil
+		}
+		return "IPv6", nil
+	},
+	"join": strings.Join,
+}
+
+// parseTemplateFormat parses the template set with --format, reporting failures as usage errors.
+func parseTemplateFormat() (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(templateFormat)
+	if err != nil {
+		return nil, usageError(fmt.Errorf("invalid format: %w", err))
+	}
+	return tmpl, nil
+}
+
+// executeTemplate prints the data with the template, followed by a newline. Failures, such as
+// a field that does not exist, are caused by the template and reported as usage errors.
+func executeTemplate(tmpl *template.Template, data any) error {
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		return usageError(fmt.Errorf("invalid format: %w", err))
+	}
+	fmt.Println()
+	return nil
+}
+
+// networkTemplateData is the data model of --format for explain and for every network of divide.
+// Addresses are empty when the network does not have them, such as the broadcast address of IPv6 networks.
+type networkTemplateData struct {
+	// Network is the CIDR range, such as 10.0.0.0/16.
+	Network string
+	// Version is the IP version of the network, 4 or 6.
+	Version int
+	// BaseAddress is the first address of the network.
+	BaseAddress string
+	// Broadcast is the last address of an IPv4 network.
+	Broadcast string
+	// FirstUsable and LastUsable are the first and last address that can be assigned to a host.
+	FirstUsable string
+	LastUsable  string
+	// Netmask is the netmask of the network, such as 255.255.0.0.
+	Netmask string
+	// PrefixLength is the length of the network's prefix in bits.
+	PrefixLength int
+	// Addresses is the number of addresses in the network.
+	Addresses *big.Int
+	// HostAddresses is the number of addresses that can be assigned to hosts.
+	HostAddresses *big.Int
+}
+
+// getNetworkTemplateData returns the template data of a network, from the same details as explain.
+func getNetworkTemplateData(network *net.IPNet) *networkTemplateData {
+	details := core.GetNetworkDetails(network)
+	data := &networkTemplateData{
+		Network:       core.FormatNetwork(network),
+		Version:       6,
+		BaseAddress:   core.FormatAddress(details.BaseAddress),
+		Netmask:       core.FormatAddress(details.Netmask),
+		PrefixLength:  details.PrefixLength,
+		Addresses:     core.GetAddressCount(network),
+		HostAddresses: core.GetHostAddressCount(network),
+	}
+	if details.IsIPV4Network {
+		data.Version = 4
+		if !details.BroadcastAddressHasError {
+			data.Broadcast = details.BroadcastAddress
+		}
+	}
+	if !details.UsableAddressRangeHasError {
+		data.FirstUsable = details.FirstUsableIPAddress
+		data.LastUsable = details.LastUsableIPAddress
+	}
+	return data
+}
+
+// countTemplateData is the data model of --format for count.
+type countTemplateData struct {
+	// Network is the CIDR range, such as 10.0.0.0/16.
+	Network string
+	// Addresses is the number of addresses in the network.
+	Addresses *big.Int
+}
+
+// containsTemplateData is the data model of --format for every needle of contains.
+type containsTemplateData struct {
+	// Needle is the address or CIDR range as it was given.
+	Needle string
+	// Containment is full, partial or none.
+	Containment string
+	// Contained is true when the needle is fully contained.
+	Contained bool
+	// Networks are the CIDR ranges that contain the needle, fully or, if there are none, partially.
+	Networks []string
+}
+
+// overlapsTemplateData is the data model of --format for overlaps.
+type overlapsTemplateData struct {
+	// Network and Other are the CIDR ranges that are compared.
+	Network string
+	Other   string
+	// Overlaps is true when the CIDR ranges have addresses in common.
+	Overlaps bool
+}
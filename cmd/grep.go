@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/grep"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/spf13/cobra"
+)
+
+const (
+	grepExample = "# Print the requests from our office networks in an nginx access log\n" +
+		"cidr grep 10.0.0.0/8,192.168.0.0/16 access.log\n" +
+		"\n" +
+		"# Count the requests from outside of the networks in a file, matching the client address in the first field\n" +
+		"cidr grep --invert-match --count --field 1 --networks internal.txt access.log\n" +
+		"\n" +
+		"# Print the addresses of a CSV column that belong to a cloud provider\n" +
+		"cidr grep --only-matching --field 3 --delimiter , --networks aws-ranges.txt flows.csv\n" +
+		"\n" +
+		"# Filter syslog from stdin\n" +
+		"journalctl | cidr grep 2001:db8::/32"
+)
+
+var (
+	grepInvertMatch  bool
+	grepCount        bool
+	grepOnlyMatching bool
+	grepField        int
+	grepDelimiter    string
+	grepNetworksFile string
+
+	grepCmd = &cobra.Command{
+		Use:   "grep <network>[,<network>...] [file...]",
+		Short: "Prints the lines of text or logs that hold an address in the given CIDR ranges",
+		Long: "Prints the lines of text, such as nginx, load balancer or syslog logs and CSV files, that hold an IPv4\n" +
+			"or IPv6 address in one of the CIDR ranges. The first argument is a comma separated list of CIDR ranges\n" +
+			"and addresses, unless --networks is given. Without files, or with '-', the text is read from stdin.\n" +
+			"\n" +
+			"Exits with 0 when any line is selected, and with 1 otherwise.",
+		Example: grepExample,
+		RunE:    executeGrep,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+	grepCmd.Flags().BoolVarP(&grepInvertMatch, "invert-match", "v", false, "select the lines without an address in the CIDR ranges")
+	grepCmd.Flags().BoolVarP(&grepCount, "count", "c", false, "only print the number of selected lines")
+	grepCmd.Flags().BoolVarP(&grepOnlyMatching, "only-matching", "o", false, "only print the matching addresses, one per line")
+	grepCmd.Flags().IntVar(&grepField, "field", 0, "only match the addresses in this 1-based field of a line (default: any field)")
+	grepCmd.Flags().StringVar(&grepDelimiter, "delimiter", "", "delimiter of the fields of a line (default: whitespace)")
+	grepCmd.Flags().StringVar(&grepNetworksFile, "networks", "", "file with the CIDR ranges to match, '-' for stdin")
+	addInputFormatFlag(grepCmd.Flags())
+	grepCmd.MarkFlagsMutuallyExclusive("invert-match", "only-matching")
+	grepCmd.MarkFlagsMutuallyExclusive("count", "only-matching")
+}
+
+func executeGrep(cmd *cobra.Command, args []string) error {
+	if grepField < 0 {
+		return usageError(fmt.Errorf("field must be at least 1, got %d", grepField))
+	}
+
+	networks, files, err := grepArguments(args)
+	if err != nil {
+		return err
+	}
+
+	options := grep.Options{
+		Networks:     core.NewIPSet(networks...),
+		Field:        grepField,
+		Delimiter:    grepDelimiter,
+		Invert:       grepInvertMatch,
+		OnlyMatching: grepOnlyMatching,
+		Unmap:        unmap,
+	}
+	var out io.Writer = os.Stdout
+	if grepCount {
+		out = io.Discard
+	}
+
+	selected := 0
+	for _, name := range files {
+		if len(files) > 1 {
+			options.Prefix = name + ":"
+		}
+		count, err := grepFile(name, out, options)
+		if err != nil {
+			return inputError(err)
+		}
+		if grepCount {
+			fmt.Printf("%s%d\n", options.Prefix, count)
+		}
+		selected += count
+	}
+	if selected == 0 {
+		return errFalse
+	}
+	return nil
+}
+
+// grepArguments collects the networks from the first argument or the networks file, and the files to search.
+func grepArguments(args []string) ([]*net.IPNet, []string, error) {
+	var networks []*net.IPNet
+	if grepNetworksFile != "" {
+		fileNetworks, err := readNetworks(grepNetworksFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		networks = fileNetworks
+	} else if len(args) > 0 {
+		for _, arg := range strings.Split(args[0], ",") {
+			network, err := parsePrefix(arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			networks = append(networks, network)
+		}
+		args = args[1:]
+	}
+	if len(networks) == 0 {
+		return nil, nil, usageError(errors.New("provide the CIDR ranges to match"))
+	}
+
+	if len(args) == 0 {
+		args = []string{input.Stdin}
+	}
+	if grepNetworksFile == input.Stdin && slices.Contains(args, input.Stdin) {
+		return nil, nil, usageError(errors.New("the CIDR ranges and the text cannot both be read from stdin"))
+	}
+	return networks, args, nil
+}
+
+// grepFile writes the selected lines of the named file to w, or of stdin if the name is '-'.
+func grepFile(name string, w io.Writer, options grep.Options) (int, error) {
+	f, err := input.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	count, err := grep.Grep(f, w, options)
+	if err != nil {
+		return count, fmt.Errorf("%s: %w", name, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	iidExample = "# Predict the addresses a host assigns itself with SLAAC\n" +
+		"cidr iid 2001:db8:1:2::/64 00:1a:2b:3c:4d:5e\n" +
+		"\n" +
+		"# Include the RFC 7217 stable privacy address, given the secret inputs of the host\n" +
+		"cidr iid --secret-key s3cr3t --interface eth0 2001:db8:1:2::/64 00:1a:2b:3c:4d:5e\n" +
+		"\n" +
+		"# Find the MAC address of the host behind an EUI-64 address\n" +
+		"cidr iid 2001:db8:1:2:21a:2bff:fe3c:4d5e"
+)
+
+var (
+	iidSecretKey  string
+	iidInterface  string
+	iidNetworkID  string
+	iidDADCounter uint8
+
+	iidCmd = &cobra.Command{
+		Use:   "iid <prefix> <mac> | iid <address>",
+		Short: "Computes the IPv6 addresses a host assigns itself from its MAC address",
+		Long: "Computes the IPv6 addresses that a host with the given MAC address or EUI-64 assigns itself in a /64\n" +
+			"with SLAAC: the modified EUI-64 address and the link-local address, and with --secret-key also the\n" +
+			"stable privacy address of RFC 7217. Given a single address, it prints the MAC address that its EUI-64\n" +
+			"interface identifier was made of instead.",
+		Example: iidExample,
+		Args:    usageArgs("provide a /64 prefix and a MAC address, or an EUI-64 address", cobra.RangeArgs(1, 2)),
+		RunE:    executeIID,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(iidCmd)
+	iidCmd.Flags().StringVar(&iidSecretKey, "secret-key", "", "secret key of the host to compute the stable privacy address with")
+	iidCmd.Flags().StringVar(&iidInterface, "interface", "", "stable identifier of the interface, such as its name (default: the modified EUI-64 of the MAC address)")
+	iidCmd.Flags().StringVar(&iidNetworkID, "network-id", "", "identifier of the network the host is attached to, such as the SSID")
+	iidCmd.Flags().Uint8Var(&iidDADCounter, "dad-counter", 0, "number of duplicate address detection conflicts")
+}
+
+func executeIID(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		ip, err := parseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		mac, err := core.GetMACFromEUI64Address(ip)
+		if err != nil {
+			return inputError(err)
+		}
+		fmt.Println(mac)
+		return nil
+	}
+
+	prefix, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+	eui64, err := core.ParseEUI64(args[1])
+	if err != nil {
+		return inputError(err)
+	}
+
+	address, err := core.GetEUI64Address(prefix, eui64)
+	if err != nil {
+		return inputError(err)
+	}
+	linkLocal, err := core.GetEUI64Address(core.LinkLocalNetwork, eui64)
+	if err != nil {
+		return inputError(err)
+	}
+	fmt.Printf(color.BlueString("EUI-64 Address:\t\t ")+"%s\n", core.FormatAddress(address))
+	fmt.Printf(color.BlueString("Link-Local Address:\t ")+"%s\n", core.FormatAddress(linkLocal))
+
+	if iidSecretKey == "" {
+		return nil
+	}
+	netIface := []byte(iidInterface)
+	if iidInterface == "" {
+		netIface = eui64
+	}
+	stable, err := core.GetStablePrivacyAddress(prefix, netIface, []byte(iidNetworkID), iidDADCounter, []byte(iidSecretKey))
+	if err != nil {
+		return inputError(err)
+	}
+	fmt.Printf(color.BlueString("Stable Privacy Address:\t ")+"%s\n", core.FormatAddress(stable))
+	return nil
+}
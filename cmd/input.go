@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"net"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/spf13/pflag"
+)
+
+// inputFormat is the format of the lists that are read, as set with --input-format.
+var inputFormat string
+
+// addInputFormatFlag adds the --input-format flag to the flags of a command that reads lists.
+func addInputFormatFlag(flags *pflag.FlagSet) {
+	names := make([]string, 0, len(input.Formats()))
+	for _, format := range input.Formats() {
+		names = append(names, string(format))
+	}
+	flags.StringVar(&inputFormat, "input-format", string(input.FormatPlain),
+		"format of the lists that are read, one of "+strings.Join(names, ", "))
+}
+
+// readLines reads all prefixes from the named file in the format set with --input-format,
+// or from stdin if the name is '-'.
+func readLines(name string) ([]input.Line, error) {
+	format, err := input.ParseFormat(inputFormat)
+	if err != nil {
+		return nil, usageError(err)
+	}
+	lines, err := input.LinesFile(name, format)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	return lines, nil
+}
+
+// readEntries reads and parses all prefixes from the named file in the format set with
+// --input-format, or from stdin if the name is '-'.
+func readEntries(name string) ([]input.Entry, error) {
+	format, err := input.ParseFormat(inputFormat)
+	if err != nil {
+		return nil, usageError(err)
+	}
+	entries, err := input.ReadFile(name, format)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	for i := range entries {
+		entries[i].Network = unmapNetwork(entries[i].Network)
+	}
+	return entries, nil
+}
+
+// parsePrefix parses a CIDR range or IP address argument, reporting failures as invalid input.
+func parsePrefix(arg string) (*net.IPNet, error) {
+	network, err := input.ParsePrefix(arg)
+	if err != nil {
+		return nil, inputError(err)
+	}
+	return unmapNetwork(network), nil
+}
+
+// readNetworks reads all prefixes from the named file, or from stdin if the name is '-'.
+func readNetworks(name string) ([]*net.IPNet, error) {
+	entries, err := readEntries(name)
+	if err != nil {
+		return nil, err
+	}
+	networks := make([]*net.IPNet, len(entries))
+	for i, entry := range entries {
+		networks[i] = entry.Network
+	}
+	return networks, nil
+}
+
+// readLabelledNetworks collects prefixes from "<CIDR>[=<label>]" arguments, followed by the prefixes
+// and labels in the named file, if any.
+func readLabelledNetworks(args []string, name string) ([]input.Entry, error) {
+	var entries []input.Entry
+	for _, arg := range args {
+		prefix, label, _ := strings.Cut(arg, "=")
+		network, err := parseNetwork(prefix)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, input.Entry{Network: network, Text: prefix, Label: label})
+	}
+	if name != "" {
+		fileEntries, err := readEntries(name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	mapExample = "# Map the allocations in a /16, one cell per /24\n" +
+		"cidr map 10.0.0.0/16 10.0.0.0/20=web 10.0.16.0/21=db 10.0.64.0/26=vpn\n" +
+		"\n" +
+		"# Read labelled allocations from a file, one \"<CIDR> <label>\" per line\n" +
+		"cidr map --allocations subnets.txt 10.0.0.0/16\n" +
+		"\n" +
+		"# Zoom in to one cell per /28, using ASCII characters only\n" +
+		"cidr map --zoom 4 --ascii 10.0.4.0/24 10.0.4.0/26=web"
+)
+
+const (
+	// maxMapZoom limits the map to 4096 cells.
+	maxMapZoom = 12
+	// defaultMapZoom gives a map of 256 cells, a /16 is mapped as /24 cells.
+	defaultMapZoom = 8
+)
+
+var (
+	mapAllocationsFile string
+	mapZoom            int
+	mapASCII           bool
+
+	mapCmd = &cobra.Command{
+		Use:   "map <network> [allocation[=label]...]",
+		Short: "Draws a map of the allocations in a network",
+		Long: "Draws a map of a network as a grid of equally sized cells, showing which cells are allocated,\n" +
+			"partially allocated or free. Every allocation gets its own symbol and colour, explained in a legend.\n" +
+			"Colours are left out when stdout is not a terminal.",
+		Example: mapExample,
+		Args:    usageArgs("provide a CIDR range and its allocations", cobra.MinimumNArgs(1)),
+		RunE:    executeMap,
+	}
+
+	mapSymbols = []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789")
+	mapColors  = []color.Attribute{color.FgGreen, color.FgYellow, color.FgMagenta, color.FgCyan, color.FgBlue, color.FgRed}
+)
+
+func init() {
+	rootCmd.AddCommand(mapCmd)
+	mapCmd.Flags().StringVar(&mapAllocationsFile, "allocations", "", "file with the allocated CIDR ranges and their labels, '-' for stdin")
+	mapCmd.Flags().IntVar(&mapZoom, "zoom", 0, fmt.Sprintf("number of bits below the network's prefix length that make up a cell (default %d, at most %d)", defaultMapZoom, maxMapZoom))
+	mapCmd.Flags().BoolVar(&mapASCII, "ascii", false, "only use ASCII characters")
+	addInputFormatFlag(mapCmd.Flags())
+}
+
+// mapAllocation is an allocation with the symbol and colour that represent it on the map.
+type mapAllocation struct {
+	input.Entry
+	symbol rune
+	color  *color.Color
+}
+
+// mapGlyphs are the symbols for cells that are not fully covered by a single allocation.
+type mapGlyphs struct {
+	partial, mixed, free rune
+}
+
+var (
+	unicodeMapGlyphs = mapGlyphs{partial: '▒', mixed: '▓', free: '·'}
+	asciiMapGlyphs   = mapGlyphs{partial: '+', mixed: '*', free: '.'}
+)
+
+func executeMap(cmd *cobra.Command, args []string) error {
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+	allocations, err := mapAllocations(network, args[1:])
+	if err != nil {
+		return err
+	}
+
+	ones, bits := network.Mask.Size()
+	zoom := min(defaultMapZoom, bits-ones)
+	if cmd.Flags().Changed("zoom") {
+		zoom = mapZoom
+	}
+	if zoom < 0 || zoom > maxMapZoom || zoom > bits-ones {
+		return usageError(fmt.Errorf("zoom must be between 0 and %d", min(maxMapZoom, bits-ones)))
+	}
+	cells := []net.IPNet{*network}
+	if zoom > 0 {
+		cells, err = core.DivideCIDR(network, int64(1)<<zoom)
+		if err != nil {
+			return inputError(err)
+		}
+	}
+
+	glyphs := unicodeMapGlyphs
+	if mapASCII {
+		glyphs = asciiMapGlyphs
+	}
+	printMap(cells, 1<<((zoom+1)/2), allocations, glyphs)
+	fmt.Println()
+	printMapLegend(cells[0], allocations, glyphs)
+	return nil
+}
+
+// mapAllocations collects the allocations from the arguments and file, and assigns them a symbol and colour.
+func mapAllocations(network *net.IPNet, args []string) ([]mapAllocation, error) {
+	entries, err := readLabelledNetworks(args, mapAllocationsFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > len(mapSymbols) {
+		return nil, inputError(fmt.Errorf("cannot map more than %d allocations", len(mapSymbols)))
+	}
+
+	allocations := make([]mapAllocation, len(entries))
+	for i, entry := range entries {
+		if !core.ContainsNetwork(network, entry.Network) {
+			return nil, inputError(fmt.Errorf("%s is %w %s", core.FormatNetwork(entry.Network), core.ErrOutsideNetwork, core.FormatNetwork(network)))
+		}
+		allocations[i] = mapAllocation{
+			Entry:  entry,
+			symbol: mapSymbols[i],
+			color:  color.New(mapColors[i%len(mapColors)]),
+		}
+	}
+	return allocations, nil
+}
+
+// printMap prints the cells in rows, each row starts with the first address of the row.
+func printMap(cells []net.IPNet, columns int, allocations []mapAllocation, glyphs mapGlyphs) {
+	width := 0
+	for row := 0; row < len(cells); row += columns {
+		width = max(width, len(core.FormatAddress(cells[row].IP)))
+	}
+	for row := 0; row < len(cells); row += columns {
+		var line strings.Builder
+		for _, cell := range cells[row:min(row+columns, len(cells))] {
+			line.WriteString(" ")
+			line.WriteString(mapCell(&cell, allocations, glyphs))
+		}
+		fmt.Printf("%s%s\n", color.BlueString("%-*s", width, core.FormatAddress(cells[row].IP)), line.String())
+	}
+}
+
+// mapCell returns the symbol that represents the cell: the symbol of the allocation that covers it, or a
+// glyph for a cell that is partially allocated, allocated to multiple allocations or free.
+func mapCell(cell *net.IPNet, allocations []mapAllocation, glyphs mapGlyphs) string {
+	var overlapping []mapAllocation
+	for _, allocation := range allocations {
+		switch core.GetContainment(allocation.Network, cell) {
+		case core.FullyContained:
+			return allocation.color.Sprint(string(allocation.symbol))
+		case core.PartiallyContained:
+			overlapping = append(overlapping, allocation)
+		}
+	}
+	switch len(overlapping) {
+	case 0:
+		return string(glyphs.free)
+	case 1:
+		return overlapping[0].color.Sprint(string(glyphs.partial))
+	default:
+		return string(glyphs.mixed)
+	}
+}
+
+func printMapLegend(cell net.IPNet, allocations []mapAllocation, glyphs mapGlyphs) {
+	ones, _ := cell.Mask.Size()
+	fmt.Printf(color.BlueString("Cell size:\t ")+"/%d (%s addresses)\n", ones, formatCount(core.GetAddressCount(&cell)))
+
+	width := 0
+	for _, allocation := range allocations {
+		width = max(width, len(core.FormatNetwork(allocation.Network)))
+	}
+	for _, allocation := range allocations {
+		label := allocation.Label
+		if label != "" {
+			label = "  " + label
+		}
+		fmt.Printf("%s  %-*s%s\n", allocation.color.Sprint(string(allocation.symbol)), width, core.FormatNetwork(allocation.Network), label)
+	}
+	fmt.Printf("%c  partially allocated\n", glyphs.partial)
+	fmt.Printf("%c  multiple allocations\n", glyphs.mixed)
+	fmt.Printf("%c  free\n", glyphs.free)
+}
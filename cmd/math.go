@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	mathExample = "# The address that follows 10.0.0.255\n" +
+		"cidr math add 10.0.0.255 1\n" +
+		"\n" +
+		"# The number of addresses from 10.0.0.5 to 10.0.3.7\n" +
+		"cidr math diff 10.0.0.5 10.0.3.7\n" +
+		"\n" +
+		"# Address #1000 of a network, counting from 0\n" +
+		"cidr math nth 10.0.0.0/16 1000\n" +
+		"\n" +
+		"# The last address of a network, '--' ends the flags so that -1 is not read as one\n" +
+		"cidr math nth -- 2001:db8::/64 -1\n" +
+		"\n" +
+		"# Convert addresses to and from integers\n" +
+		"cidr math toint 10.0.0.1\n" +
+		"cidr math fromint --ipv6 1"
+)
+
+var (
+	mathFromIntIPv6 bool
+
+	mathCmd = &cobra.Command{
+		Use:   "math",
+		Short: "Performs arithmetic on IP addresses",
+		Long: "Performs arithmetic on IPv4 and IPv6 addresses. Integers may be written in decimal, or in hexadecimal,\n" +
+			"octal or binary with a 0x, 0o or 0b prefix. Results that fall outside of the address family of the\n" +
+			"address are reported as invalid input.",
+		Example: mathExample,
+	}
+
+	mathAddCmd = &cobra.Command{
+		Use:   "add <address> <integer>",
+		Short: "Prints the address that comes the given number of addresses after an address",
+		Args:  usageArgs("provide an IP address and an integer", cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeMathAdd(args[0], args[1], false)
+		},
+	}
+
+	mathSubCmd = &cobra.Command{
+		Use:   "sub <address> <integer>",
+		Short: "Prints the address that comes the given number of addresses before an address",
+		Args:  usageArgs("provide an IP address and an integer", cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeMathAdd(args[0], args[1], true)
+		},
+	}
+
+	mathDiffCmd = &cobra.Command{
+		Use:   "diff <address> <address>",
+		Short: "Prints the number of addresses from the first address to the second, negative if it comes before",
+		Args:  usageArgs("provide 2 IP addresses", cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			b, err := parseAddress(args[1])
+			if err != nil {
+				return err
+			}
+			distance, err := core.GetAddressDistance(a, b)
+			if err != nil {
+				return inputError(err)
+			}
+			fmt.Println(distance)
+			return nil
+		},
+	}
+
+	mathNthCmd = &cobra.Command{
+		Use:   "nth <network> <index>",
+		Short: "Prints the address at an index of a network, counting from 0, or back from -1 for the last address",
+		Args:  usageArgs("provide a CIDR range and an index", cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := parseNetwork(args[0])
+			if err != nil {
+				return err
+			}
+			n, err := parseInteger(args[1])
+			if err != nil {
+				return err
+			}
+			ip, err := core.GetNthAddress(network, n)
+			if err != nil {
+				return inputError(err)
+			}
+			fmt.Println(core.FormatAddress(ip))
+			return nil
+		},
+	}
+
+	mathToIntCmd = &cobra.Command{
+		Use:   "toint <address>",
+		Short: "Prints the integer value of an address",
+		Args:  usageArgs("provide an IP address", cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ip, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(core.AddressToInt(ip))
+			return nil
+		},
+	}
+
+	mathFromIntCmd = &cobra.Command{
+		Use:   "fromint <integer>",
+		Short: "Prints the address with an integer value, IPv4 if it fits and IPv6 otherwise",
+		Args:  usageArgs("provide an integer", cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, err := parseInteger(args[0])
+			if err != nil {
+				return err
+			}
+			ipv4 := !mathFromIntIPv6 && n.BitLen() <= 8*net.IPv4len
+			ip, err := core.IntToAddress(n, ipv4)
+			if err != nil {
+				return inputError(err)
+			}
+			fmt.Println(core.FormatAddress(ip))
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(mathCmd)
+	mathCmd.AddCommand(mathAddCmd, mathSubCmd, mathDiffCmd, mathNthCmd, mathToIntCmd, mathFromIntCmd)
+	mathFromIntCmd.Flags().BoolVar(&mathFromIntIPv6, "ipv6", false, "always print an IPv6 address, also for integers that fit in IPv4")
+}
+
+// executeMathAdd prints the address that comes n addresses after the address, or before it if subtract is set.
+func executeMathAdd(address, integer string, subtract bool) error {
+	ip, err := parseAddress(address)
+	if err != nil {
+		return err
+	}
+	n, err := parseInteger(integer)
+	if err != nil {
+		return err
+	}
+	if subtract {
+		n.Neg(n)
+	}
+	result, err := core.AddToAddress(ip, n)
+	if err != nil {
+		return inputError(err)
+	}
+	fmt.Println(core.FormatAddress(result))
+	return nil
+}
+
+// parseInteger parses an integer argument of arbitrary size, reporting failures as invalid input.
+func parseInteger(arg string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(arg, 0)
+	if !ok {
+		return nil, inputError(fmt.Errorf("invalid integer: %s", arg))
+	}
+	return n, nil
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"net"
-	"os"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
 	"github.com/spf13/cobra"
@@ -14,38 +13,54 @@ const (
 		"cidr overlaps 10.0.0.0/16 10.0.14.0/22\n" +
 		"\n" +
 		"# Check whether 2 IPv6 CIDR ranges overlap\n" +
-		"cidr overlaps 2001:db8:1111:2222:1::/80 2001:db8:1111:2222:1:1::/96"
+		"cidr overlaps 2001:db8:1111:2222:1::/80 2001:db8:1111:2222:1:1::/96\n" +
+		"\n" +
+		"# Only use the exit code, 0 when the ranges overlap and 1 when they do not\n" +
+		"cidr overlaps --quiet 10.0.0.0/16 10.0.14.0/22"
 )
 
-var overlapsCmd = &cobra.Command{
-	Use:     "overlaps",
-	Short:   "Checks if a CIDR range overlaps with another CIDR range",
-	Example: overlapsExample,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 2 {
-			fmt.Println("error: provide 2 CIDR ranges")
-			fmt.Println("See 'cidr overlaps -h' for help and examples")
-			os.Exit(1)
-		}
-		network1, err := core.ParseCIDR(args[0])
-		if err != nil {
-			fmt.Printf("error: %s\n", err)
-			fmt.Println("See 'cidr overlaps -h' for help and examples")
-			os.Exit(1)
-		}
-		network2, err := core.ParseCIDR(args[1])
-		if err != nil {
-			fmt.Printf("error: %s\n", err)
-			fmt.Println("See 'cidr overlaps -h' for help and examples")
-			os.Exit(1)
-		}
-		networksOverlap := overlaps(network1, network2)
-		fmt.Println(networksOverlap)
-	},
-}
+var (
+	overlapsQuiet bool
+
+	overlapsCmd = &cobra.Command{
+		Use:     "overlaps",
+		Short:   "Checks if a CIDR range overlaps with another CIDR range",
+		Example: overlapsExample,
+		Args:    usageArgs("provide 2 CIDR ranges", cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network1, err := parseNetwork(args[0])
+			if err != nil {
+				return err
+			}
+			network2, err := parseNetwork(args[1])
+			if err != nil {
+				return err
+			}
+			networksOverlap := overlaps(network1, network2)
+			if templateFormat != "" && !overlapsQuiet {
+				tmpl, err := parseTemplateFormat()
+				if err != nil {
+					return err
+				}
+				data := &overlapsTemplateData{Network: core.FormatNetwork(network1), Other: core.FormatNetwork(network2), Overlaps: networksOverlap}
+				if err := executeTemplate(tmpl, data); err != nil {
+					return err
+				}
+			} else if !overlapsQuiet {
+				fmt.Println(networksOverlap)
+			}
+			if !networksOverlap {
+				return errFalse
+			}
+			return nil
+		},
+	}
+)
 
 func init() {
 	rootCmd.AddCommand(overlapsCmd)
+	overlapsCmd.Flags().BoolVarP(&overlapsQuiet, "quiet", "q", false, "do not print the result, only set the exit code")
+	addFormatFlag(overlapsCmd.Flags())
 }
 
 func overlaps(network1, network2 *net.IPNet) bool {
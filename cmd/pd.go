@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pdExample = "# Plan 2 regions of 4 sites with 2 buildings of 4 VLANs each, in a /32 from the RIR\n" +
+		"cidr pd 2001:db8::/32 region:4:2 site:12:4 building:8:2 vlan:8:4\n" +
+		"\n" +
+		"# Export the plan as a table, one prefix per line\n" +
+		"cidr pd --output table 2001:db8::/48 building:8:4 vlan:8:16\n" +
+		"\n" +
+		"# Export the plan as JSON\n" +
+		"cidr pd --output json 2001:db8:100::/40 site:8:2 vlan:16:2"
+)
+
+// maxDelegations limits the size of a plan, to keep a level without count from flooding the output.
+const maxDelegations = 1 << 20
+
+var (
+	pdOutput string
+
+	pdCmd = &cobra.Command{
+		Use:   "pd <network> <level>...",
+		Short: "Plans a hierarchy of prefixes, such as regions, sites and VLANs, in a delegated prefix",
+		Long: "Plans a hierarchy of prefixes in a delegated prefix, such as the /32 from a RIR. Every level is given\n" +
+			"as name:bits:count, where bits is the number of bits that the level adds to the prefix length of the\n" +
+			"level above it, and count is the number of prefixes to allocate in every prefix of that level. Without\n" +
+			"a count, the level is allocated in full. Prefixes are numbered from 0, which is also their subnet ID.\n" +
+			"\n" +
+			"For IPv6 plans, it warns about levels that are not on a nibble boundary, which makes their reverse DNS\n" +
+			"zones harder to delegate and their prefixes harder to read, and about LAN subnets that are not /64.",
+		Example: pdExample,
+		Args:    usageArgs("provide a CIDR range and at least 1 level", cobra.MinimumNArgs(2)),
+		RunE:    executePD,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(pdCmd)
+	pdCmd.Flags().StringVarP(&pdOutput, "output", "o", "text", "output format, one of text, table or json")
+}
+
+type delegationResult struct {
+	Name     string              `json:"name"`
+	Level    string              `json:"level,omitempty"`
+	Prefix   string              `json:"prefix"`
+	Children []*delegationResult `json:"children,omitempty"`
+}
+
+type pdResult struct {
+	Plan     *delegationResult `json:"plan"`
+	Warnings []string          `json:"warnings"`
+}
+
+func executePD(cmd *cobra.Command, args []string) error {
+	if pdOutput != "text" && pdOutput != "table" && pdOutput != "json" {
+		return usageError(fmt.Errorf("unknown output format: %s", pdOutput))
+	}
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+	levels := make([]core.DelegationLevel, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		level, err := core.ParseDelegationLevel(arg)
+		if err != nil {
+			return usageError(err)
+		}
+		levels = append(levels, level)
+	}
+	if count := core.GetDelegationCount(levels); count.Cmp(big.NewInt(maxDelegations)) > 0 {
+		return usageError(fmt.Errorf("the plan has %s prefixes, give the levels a count to stay below %d", count, maxDelegations))
+	}
+
+	plan, err := core.GetDelegationPlan(network, levels)
+	if err != nil {
+		return inputError(err)
+	}
+	warnings := core.GetDelegationWarnings(network, levels)
+
+	switch pdOutput {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&pdResult{Plan: getDelegationResult(plan), Warnings: append([]string{}, warnings...)})
+	case "table":
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+		fmt.Fprintln(w, "Path\tLevel\tPrefix")
+		printDelegationTable(w, plan.Children, nil)
+		return w.Flush()
+	default:
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+		fmt.Fprintln(w, core.FormatNetwork(plan.Network))
+		printDelegationTree(w, plan.Children, 1)
+		return w.Flush()
+	}
+}
+
+func getDelegationResult(delegation *core.Delegation) *delegationResult {
+	result := &delegationResult{
+		Name:   delegation.Name(),
+		Level:  delegation.Level,
+		Prefix: core.FormatNetwork(delegation.Network),
+	}
+	for _, child := range delegation.Children {
+		result.Children = append(result.Children, getDelegationResult(child))
+	}
+	return result
+}
+
+// printDelegationTree prints the prefixes indented by their depth in the plan.
+func printDelegationTree(w *tabwriter.Writer, delegations []*core.Delegation, depth int) {
+	for _, delegation := range delegations {
+		fmt.Fprintf(w, "%s%s\t%s\n", strings.Repeat("  ", depth), delegation.Name(), core.FormatNetwork(delegation.Network))
+		printDelegationTree(w, delegation.Children, depth+1)
+	}
+}
+
+// printDelegationTable prints every prefix on its own line, with the path of names that leads to it.
+func printDelegationTable(w *tabwriter.Writer, delegations []*core.Delegation, path []string) {
+	for _, delegation := range delegations {
+		names := append(path[:len(path):len(path)], delegation.Name())
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.Join(names, "/"), delegation.Level, core.FormatNetwork(delegation.Network))
+		printDelegationTable(w, delegation.Children, names)
+	}
+}
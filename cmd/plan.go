@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/plan"
+	"github.com/spf13/cobra"
+)
+
+const (
+	planExample = "# Check that every block of an address plan sits inside its parent, without overlapping its siblings\n" +
+		"cidr plan validate network-plan.yaml\n" +
+		"\n" +
+		"# Print the plan as a tree, with the share of every block that is allocated to its children\n" +
+		"cidr plan render network-plan.yaml"
+)
+
+var (
+	planValidateOutput string
+
+	planCmd = &cobra.Command{
+		Use:   "plan",
+		Short: "Validates and renders address plans of nested, named blocks",
+		Long: "Validates and renders address plans: YAML files of nested, named blocks of address space, such as\n" +
+			"an organization that is split up in environments, regions and subnets. Every block has a name and\n" +
+			"a cidr, and optionally a size (a prefix length such as /24, or a number of addresses), a description\n" +
+			"and children:\n" +
+			"\n" +
+			"  name: acme\n" +
+			"  cidr: 10.0.0.0/8\n" +
+			"  children:\n" +
+			"    - name: prod\n" +
+			"      cidr: 10.0.0.0/16\n" +
+			"      size: /16\n" +
+			"      children:\n" +
+			"        - name: eu-west\n" +
+			"          cidr: 10.0.0.0/20",
+		Example: planExample,
+	}
+
+	planValidateCmd = &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Checks that the blocks of a plan are canonical, of the declared size, inside their parent and without overlaps",
+		Long: "Checks that every block of a plan has a canonical CIDR range of its declared size, that it is inside\n" +
+			"its parent and that it does not overlap with its siblings. Problems are reported with the line of the\n" +
+			"block in the plan, and make the command exit with 1.",
+		Args: usageArgs("provide a plan file", cobra.ExactArgs(1)),
+		RunE: executePlanValidate,
+	}
+
+	planRenderCmd = &cobra.Command{
+		Use:   "render <file>",
+		Short: "Prints a plan as a tree, with the share of every block that is allocated to its children",
+		Args:  usageArgs("provide a plan file", cobra.ExactArgs(1)),
+		RunE:  executePlanRender,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.AddCommand(planValidateCmd, planRenderCmd)
+	planValidateCmd.Flags().StringVarP(&planValidateOutput, "output", "o", "text", "output format, one of text or json")
+}
+
+// loadPlan reads a plan from the named file, or from stdin if the name is '-'.
+func loadPlan(name string) (*plan.Block, error) {
+	var root *plan.Block
+	var err error
+	if name == input.Stdin {
+		root, err = plan.Parse(os.Stdin)
+	} else {
+		root, err = plan.Load(name)
+	}
+	if err != nil {
+		return nil, inputError(err)
+	}
+	root.Walk(func(block *plan.Block) {
+		if block.Network != nil {
+			block.Network = unmapNetwork(block.Network)
+		}
+	})
+	return root, nil
+}
+
+func executePlanValidate(cmd *cobra.Command, args []string) error {
+	if planValidateOutput != "text" && planValidateOutput != "json" {
+		return usageError(fmt.Errorf("unknown output format: %s", planValidateOutput))
+	}
+	root, err := loadPlan(args[0])
+	if err != nil {
+		return err
+	}
+	problems := plan.Validate(root)
+
+	if planValidateOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(append([]plan.Problem{}, problems...)); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range problems {
+			fmt.Printf("%s:%d: %s: %s\n", args[0], problem.Line, problem.Path, problem.Message)
+		}
+	}
+	if len(problems) > 0 {
+		return errFalse
+	}
+	return nil
+}
+
+func executePlanRender(cmd *cobra.Command, args []string) error {
+	root, err := loadPlan(args[0])
+	if err != nil {
+		return err
+	}
+	if problems := plan.Validate(root); len(problems) > 0 {
+		return inputError(fmt.Errorf("plan has %d problems, see 'cidr plan validate %s'", len(problems), args[0]))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	if err := renderBlock(w, root, 0); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// renderBlock prints the block indented by its depth in the plan, followed by its children.
+func renderBlock(w *tabwriter.Writer, block *plan.Block, depth int) error {
+	line := fmt.Sprintf("%s%s\t%s\t%s", strings.Repeat("  ", depth), block.Name, core.FormatNetwork(block.Network),
+		helper.FormatNumber(core.GetAddressCount(block.Network).String()))
+	if len(block.Children) > 0 {
+		children := make([]*net.IPNet, len(block.Children))
+		for i, child := range block.Children {
+			children[i] = child.Network
+		}
+		utilization, err := core.GetUtilization(block.Network, children)
+		if err != nil {
+			return inputError(err)
+		}
+		line += fmt.Sprintf("\t%.2f%% allocated", utilization.GetUsedPercentage())
+	}
+	fmt.Fprintln(w, line)
+
+	for _, child := range block.Children {
+		if err := renderBlock(w, child, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
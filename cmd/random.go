@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	randomExample = "# Pick a random address in a network\n" +
+		"cidr random 10.0.0.0/16\n" +
+		"\n" +
+		"# Pick 5 distinct /24 subnets for lab networks, avoiding the ones that are in use\n" +
+		"cidr random --prefix-length 24 --count 5 --exclude subnets.txt 10.0.0.0/8\n" +
+		"\n" +
+		"# Generate the same test fixture of 100 IPv6 addresses on every run\n" +
+		"cidr random --count 100 --seed 42 2001:db8::/32"
+)
+
+// maxRandomCount limits the number of picks, as every pick is kept in memory to make them distinct.
+const maxRandomCount = 1 << 20
+
+var (
+	randomPrefixLength int
+	randomCount        int
+	randomSeed         uint64
+	randomExcludeFile  string
+
+	randomCmd = &cobra.Command{
+		Use:   "random <network> [exclusion...]",
+		Short: "Picks random addresses or aligned subnets in a network",
+		Long: "Picks random addresses, or aligned subnets of a prefix length, in a network. Every address or subnet\n" +
+			"in the network is equally likely to be picked, also across the full 128 bits of IPv6. Subnets that\n" +
+			"overlap with an exclusion are never picked, and with --count all picks are distinct.",
+		Example: randomExample,
+		Args:    usageArgs("provide a CIDR range", cobra.MinimumNArgs(1)),
+		RunE:    executeRandom,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(randomCmd)
+	randomCmd.Flags().IntVarP(&randomPrefixLength, "prefix-length", "p", 0, "prefix length of the subnets to pick (default: single addresses)")
+	randomCmd.Flags().IntVarP(&randomCount, "count", "n", 1, "number of distinct addresses or subnets to pick")
+	randomCmd.Flags().Uint64Var(&randomSeed, "seed", 0, "seed for reproducible picks (default: a random seed)")
+	randomCmd.Flags().StringVar(&randomExcludeFile, "exclude", "", "file with CIDR ranges and addresses to avoid, '-' for stdin")
+	addInputFormatFlag(randomCmd.Flags())
+}
+
+func executeRandom(cmd *cobra.Command, args []string) error {
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+	if randomCount < 1 || randomCount > maxRandomCount {
+		return usageError(fmt.Errorf("count must be between 1 and %d, got %d", maxRandomCount, randomCount))
+	}
+	ones, bits := network.Mask.Size()
+	prefixLength := bits
+	if cmd.Flags().Changed("prefix-length") {
+		prefixLength = randomPrefixLength
+	}
+	if prefixLength < ones || prefixLength > bits {
+		return usageError(fmt.Errorf("prefix length must be between /%d and /%d for %s", ones, bits, core.FormatNetwork(network)))
+	}
+
+	var exclusions []*net.IPNet
+	for _, arg := range args[1:] {
+		exclusion, err := parsePrefix(arg)
+		if err != nil {
+			return err
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+	if randomExcludeFile != "" {
+		fileExclusions, err := readNetworks(randomExcludeFile)
+		if err != nil {
+			return err
+		}
+		exclusions = append(exclusions, fileExclusions...)
+	}
+
+	seed := randomSeed
+	if !cmd.Flags().Changed("seed") {
+		seed = rand.Uint64()
+	}
+	r := rand.New(rand.NewPCG(seed, 0))
+
+	subnets, err := core.GetRandomSubnets(r, network, prefixLength, core.NewIPSet(exclusions...), randomCount)
+	if err != nil {
+		return inputError(err)
+	}
+	for _, subnet := range subnets {
+		if prefixLength == bits {
+			fmt.Println(core.FormatAddress(subnet.IP))
+		} else {
+			fmt.Println(core.FormatNetwork(subnet))
+		}
+	}
+	return nil
+}
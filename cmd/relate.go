@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	relateExample = "# How does a requested peering range relate to our own network\n" +
+		"cidr relate 10.0.128.0/17 10.0.0.0/16\n" +
+		"\n" +
+		"# Check whether two networks can be announced as a single supernet\n" +
+		"cidr relate 192.168.0.0/24 192.168.1.0/24\n" +
+		"\n" +
+		"# Report the relationship of two IPv6 networks as JSON\n" +
+		"cidr relate --output json 2001:db8::/48 2001:db8:2::/48"
+)
+
+var (
+	relateOutput string
+
+	relateCmd = &cobra.Command{
+		Use:   "relate <network> <network>",
+		Short: "Classifies how two CIDR ranges relate to each other",
+		Long: "Classifies how two CIDR ranges relate to each other: equal, a subnet or supernet of the other,\n" +
+			"mergeable when they touch and make up a single supernet together, adjacent when they touch but do not,\n" +
+			"or disjoint. It also reports the intersection, the gap between them and their smallest common supernet.",
+		Example: relateExample,
+		Args:    usageArgs("provide 2 CIDR ranges", cobra.ExactArgs(2)),
+		RunE:    executeRelate,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(relateCmd)
+	relateCmd.Flags().StringVarP(&relateOutput, "output", "o", "text", "output format, one of text or json")
+}
+
+type relateResult struct {
+	Network      string   `json:"network"`
+	Other        string   `json:"other"`
+	Relation     string   `json:"relation"`
+	Intersection string   `json:"intersection,omitempty"`
+	Gap          []string `json:"gap"`
+	Supernet     string   `json:"supernet"`
+}
+
+func executeRelate(cmd *cobra.Command, args []string) error {
+	if relateOutput != "text" && relateOutput != "json" {
+		return usageError(fmt.Errorf("unknown output format: %s", relateOutput))
+	}
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+	other, err := parseNetwork(args[1])
+	if err != nil {
+		return err
+	}
+	relationship, err := core.GetRelationship(network, other)
+	if err != nil {
+		return inputError(err)
+	}
+
+	result := &relateResult{
+		Network:  core.FormatNetwork(network),
+		Other:    core.FormatNetwork(other),
+		Relation: relationship.Relation.String(),
+		Gap:      make([]string, len(relationship.Gap)),
+		Supernet: core.FormatNetwork(relationship.Supernet),
+	}
+	if relationship.Intersection != nil {
+		result.Intersection = core.FormatNetwork(relationship.Intersection)
+	}
+	for i, gap := range relationship.Gap {
+		result.Gap[i] = core.FormatNetwork(gap)
+	}
+
+	if relateOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	printRelationship(result, relationship.Relation)
+	return nil
+}
+
+func printRelationship(result *relateResult, relation core.Relation) {
+	var description string
+	switch relation {
+	case core.Equal:
+		description = "%s is equal to %s"
+	case core.Subnet:
+		description = "%s is a subnet of %s"
+	case core.Supernet:
+		description = "%s is a supernet of %s"
+	case core.Mergeable:
+		description = "%s and %s are adjacent and mergeable"
+	case core.Adjacent:
+		description = "%s and %s are adjacent, but not mergeable"
+	default:
+		description = "%s and %s are disjoint"
+	}
+	fmt.Printf(color.BlueString("Relation:\t\t ")+description+"\n", result.Network, result.Other)
+
+	intersection := result.Intersection
+	if intersection == "" {
+		intersection = "none"
+	}
+	fmt.Printf(color.BlueString("Intersection:\t\t ")+"%s\n", intersection)
+
+	gap := strings.Join(result.Gap, ", ")
+	if gap == "" {
+		gap = "none"
+	}
+	fmt.Printf(color.BlueString("Gap:\t\t\t ")+"%s\n", gap)
+	fmt.Printf(color.BlueString("Common Supernet:\t ")+"%s\n", result.Supernet)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/bschaatsbergen/cidr/internal/render"
+	"github.com/spf13/cobra"
+)
+
+const (
+	renderExample = "# Render the allocations in a /8 as a PNG image, one pixel per /24\n" +
+		"cidr render --allocations subnets.txt --file map.png 10.0.0.0/8\n" +
+		"\n" +
+		"# Colour the allocations by their label and write an SVG image\n" +
+		"cidr render --color-by label --output svg --file map.svg 10.0.0.0/16 10.0.0.0/20=web 10.0.64.0/18=db\n" +
+		"\n" +
+		"# Render a heatmap of the addresses in a log, one pixel per /16\n" +
+		"cidr render --counts addresses.txt --pixel-prefix-length 16 --file heatmap.png 0.0.0.0/0"
+)
+
+const (
+	colorByAllocation = "allocation"
+	colorByLabel      = "label"
+	colorByCount      = "count"
+
+	// renderMinimumSize is the size in pixels that small maps are scaled up to by default.
+	renderMinimumSize = 512
+)
+
+var (
+	renderAllocationsFile string
+	renderCountsFile      string
+	renderColorBy         string
+	renderPixelPrefixLen  int
+	renderScale           int
+	renderOutput          string
+	renderFile            string
+
+	renderCmd = &cobra.Command{
+		Use:   "render <network> [allocation[=label]...]",
+		Short: "Renders the address space of a network as an image on a Hilbert curve",
+		Long: "Renders the address space of a network as a PNG or SVG image. The addresses are laid out on a\n" +
+			"Hilbert curve, so that addresses that are close to each other end up close to each other in the image.\n" +
+			"Pixels are coloured by allocation, by the label of the allocation or by the number of addresses\n" +
+			"from the --counts file that fall in them.",
+		Example: renderExample,
+		Args:    usageArgs("provide a CIDR range", cobra.MinimumNArgs(1)),
+		RunE:    executeRender,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVar(&renderAllocationsFile, "allocations", "", "file with the allocated CIDR ranges and their labels, '-' for stdin")
+	renderCmd.Flags().StringVar(&renderCountsFile, "counts", "", "file with one IP address per line to count per pixel, '-' for stdin")
+	renderCmd.Flags().StringVar(&renderColorBy, "color-by", "", "colour pixels by allocation, label or count (default count with --counts, otherwise allocation)")
+	renderCmd.Flags().IntVar(&renderPixelPrefixLen, "pixel-prefix-length", 0, "prefix length that one pixel represents (default 16 bits below the network, at most)")
+	renderCmd.Flags().IntVar(&renderScale, "scale", 0, fmt.Sprintf("size of a pixel in the image (default scales the map up to at least %d pixels)", renderMinimumSize))
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "png", "output format, one of png or svg")
+	renderCmd.Flags().StringVar(&renderFile, "file", "", "file to write the image to (default stdout)")
+	addInputFormatFlag(renderCmd.Flags())
+}
+
+func executeRender(cmd *cobra.Command, args []string) error {
+	if renderOutput != "png" && renderOutput != "svg" {
+		return usageError(fmt.Errorf("unknown output format: %s", renderOutput))
+	}
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+
+	m, err := render.NewMap(network, renderPixelPrefixLength(network))
+	if err != nil {
+		return usageError(err)
+	}
+	if err := colorRenderMap(m, args[1:]); err != nil {
+		return err
+	}
+
+	scale := renderScale
+	if scale <= 0 {
+		scale = max(1, renderMinimumSize/m.Side())
+	}
+	if scale > m.MaxScale() {
+		return usageError(fmt.Errorf("scale must be at most %d, so that the image is at most %d pixels wide", m.MaxScale(), render.MaxImageSize))
+	}
+
+	var w io.Writer = os.Stdout
+	if renderFile != "" {
+		f, err := os.Create(renderFile)
+		if err != nil {
+			return inputError(err)
+		}
+		defer f.Close()
+		w = f
+	} else if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 && renderOutput == "png" {
+		return usageError(fmt.Errorf("refusing to write a PNG image to a terminal, use --file or redirect stdout"))
+	}
+
+	if renderOutput == "svg" {
+		return render.WriteSVG(w, m, scale)
+	}
+	return render.WritePNG(w, m, scale)
+}
+
+// renderPixelPrefixLength returns the pixel prefix length from the flag, or a map of at most 256 by 256 pixels.
+func renderPixelPrefixLength(network *net.IPNet) int {
+	if renderPixelPrefixLen > 0 {
+		return renderPixelPrefixLen
+	}
+	ones, bits := network.Mask.Size()
+	return ones + min(16, (bits-ones)/2*2)
+}
+
+// colorRenderMap colours the map as requested by the flags.
+func colorRenderMap(m *render.Map, args []string) error {
+	colorBy := renderColorBy
+	if colorBy == "" {
+		colorBy = colorByAllocation
+		if renderCountsFile != "" {
+			colorBy = colorByCount
+		}
+	}
+
+	switch colorBy {
+	case colorByCount:
+		if renderCountsFile == "" {
+			return usageError(fmt.Errorf("colouring by count requires --counts"))
+		}
+		entries, err := readEntries(renderCountsFile)
+		if err != nil {
+			return err
+		}
+		addresses := make([]net.IP, len(entries))
+		for i, entry := range entries {
+			addresses[i] = entry.Network.IP
+		}
+		m.ColorByCount(addresses)
+		return nil
+	case colorByAllocation, colorByLabel:
+		entries, err := readLabelledNetworks(args, renderAllocationsFile)
+		if err != nil {
+			return err
+		}
+		if colorBy == colorByAllocation {
+			networks := make([]*net.IPNet, len(entries))
+			for i, entry := range entries {
+				networks[i] = entry.Network
+			}
+			err = m.ColorByAllocation(networks)
+		} else {
+			allocations := make([]render.Allocation, len(entries))
+			for i, entry := range entries {
+				allocations[i] = render.Allocation{Network: entry.Network, Label: entry.Label}
+			}
+			err = m.ColorByLabel(allocations)
+		}
+		if err != nil {
+			return inputError(err)
+		}
+		return nil
+	default:
+		return usageError(fmt.Errorf("unknown colour mode: %s", colorBy))
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"strings"
 
@@ -12,20 +11,42 @@ import (
 var (
 	version string
 
+	// unmap is set with --unmap, it makes commands treat IPv4-mapped IPv6 networks and addresses
+	// as the IPv4 ones they stand for.
+	unmap bool
+
 	rootCmd = &cobra.Command{
 		Use:     "cidr",
 		Short:   "cidr - CLI to perform various actions on CIDR ranges",
+		Long:    rootLong,
 		Version: version, // The version is set during the build by making using of `go build -ldflags`.
-		Run: func(cmd *cobra.Command, args []string) {
-			err := cmd.Help()
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.NoArgs(cmd, args); err != nil {
+				return usageError(err)
 			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
 		},
+		// Errors are printed by Execute, which also decides on the exit code.
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 )
 
+const rootLong = "cidr - CLI to perform various actions on CIDR ranges\n" +
+	"\n" +
+	"Exit codes:\n" +
+	"  0  success, or the check holds (true)\n" +
+	"  1  the check does not hold (false)\n" +
+	"  2  usage error\n" +
+	"  3  invalid input\n" +
+	"\n" +
+	"IPv4 and IPv6 are strictly separate: networks and addresses of different families never contain or\n" +
+	"overlap each other. IPv4-mapped IPv6 networks and addresses, such as ::ffff:10.0.0.0/104, are IPv6 unless\n" +
+	"--unmap is given, which treats them as the IPv4 ones they stand for, 10.0.0.0/8 in this case."
+
 func setupCobraUsageTemplate() {
 	cobra.AddTemplateFunc("StyleHeading", color.New(color.FgBlue).SprintFunc())
 	usageTemplate := rootCmd.UsageTemplate()
@@ -41,11 +62,14 @@ func setupCobraUsageTemplate() {
 func init() {
 	setupCobraUsageTemplate()
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().BoolVar(&unmap, "unmap", false, "treat IPv4-mapped IPv6 networks and addresses, such as ::ffff:10.0.0.0/104, as IPv4")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err)
+	})
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		os.Exit(handleError(cmd, err))
 	}
 }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+	"github.com/bschaatsbergen/cidr/internal/scan"
+	"github.com/spf13/cobra"
+)
+
+const (
+	scanExample = "# Audit the CIDR ranges in the current directory\n" +
+		"cidr scan\n" +
+		"\n" +
+		"# Audit a Terraform module and a Helm chart, and report the findings as SARIF for code scanning\n" +
+		"cidr scan --output sarif infra/ charts/ > cidr.sarif\n" +
+		"\n" +
+		"# List every CIDR range and IP address found in Ansible variables\n" +
+		"cidr scan --list --include '*.yml' group_vars/"
+)
+
+var (
+	scanConfigFile string
+	scanOutput     string
+	scanFailOn     string
+	scanInclude    []string
+	scanList       bool
+
+	scanCmd = &cobra.Command{
+		Use:   "scan [path...]",
+		Short: "Finds and audits the CIDR ranges and IP addresses in IaC and configuration files",
+		Long: "Walks the given directories, the current directory by default, and finds every IPv4 and IPv6 CIDR range and\n" +
+			"address in Terraform, Kubernetes, Helm, Ansible and JSON files. The findings are linted with the rules of\n" +
+			"'cidr validate': overlaps between distinct CIDR ranges, host bits set and special-purpose ranges other than\n" +
+			"private, loopback, link-local and unspecified are reported. Duplicates are not reported, as the same CIDR\n" +
+			"range is usually referenced from many places. A --config file is applied on top of these defaults.\n" +
+			"\n" +
+			"Overlaps are often intended, such as a subnet inside its VPC, so they are reported as warning and do not\n" +
+			"fail the default --fail-on error. Use --fail-on warning to fail on overlaps as well, or raise the severity\n" +
+			"of the overlap rule to error with --config.",
+		Example: scanExample,
+		RunE:    executeScan,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+	scanCmd.Flags().StringVar(&scanConfigFile, "config", "", "YAML file that configures the severity and options of the rules")
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "text", "output format, one of text, json or sarif")
+	scanCmd.Flags().StringVar(&scanFailOn, "fail-on", "error", "lowest severity that makes the command exit with 1, one of info, warning, error or off")
+	scanCmd.Flags().StringSliceVar(&scanInclude, "include", scan.DefaultPatterns, "file name patterns to scan")
+	scanCmd.Flags().BoolVar(&scanList, "list", false, "list the CIDR ranges and IP addresses that were found instead of linting them")
+}
+
+func executeScan(cmd *cobra.Command, args []string) error {
+	failOn, err := lint.ParseSeverity(scanFailOn)
+	if err != nil {
+		return usageError(err)
+	}
+	if scanOutput != "text" && scanOutput != "json" && (scanOutput != "sarif" || scanList) {
+		return usageError(fmt.Errorf("unknown output format: %s", scanOutput))
+	}
+
+	config := scan.DefaultConfig()
+	if scanConfigFile != "" {
+		userConfig, err := lint.LoadConfig(scanConfigFile)
+		if err != nil {
+			return inputError(err)
+		}
+		config = config.Merge(userConfig)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	var lines []input.Line
+	for _, root := range args {
+		rootLines, err := scan.Walk(root, scanInclude)
+		if err != nil {
+			return inputError(err)
+		}
+		lines = append(lines, rootLines...)
+	}
+
+	if scanList {
+		return writeScanList(lines)
+	}
+
+	config.Unmap = config.Unmap || unmap
+	findings := lint.Lint(lines, config)
+	switch scanOutput {
+	case "json":
+		err = lint.WriteJSON(os.Stdout, findings)
+	case "sarif":
+		err = lint.WriteSARIF(os.Stdout, lint.ToolInfo{
+			Name:           "cidr",
+			Version:        version,
+			InformationURI: "https://github.com/bschaatsbergen/cidr",
+		}, lint.Rules, findings)
+	default:
+		err = lint.WriteText(os.Stdout, findings)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range findings {
+		if failOn != lint.SeverityOff && finding.Severity >= failOn {
+			return errFalse
+		}
+	}
+	return nil
+}
+
+type scanLiteral struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Value  string `json:"value"`
+}
+
+func writeScanList(lines []input.Line) error {
+	if scanOutput == "json" {
+		literals := make([]scanLiteral, 0, len(lines))
+		for _, line := range lines {
+			literals = append(literals, scanLiteral{Source: line.Source, Line: line.Number, Column: line.Column, Value: line.Field})
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(literals)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s:%d:%d\t%s\n", line.Source, line.Number, line.Column, line.Field)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bschaatsbergen/cidr/internal/server"
+	"github.com/spf13/cobra"
+)
+
+const (
+	serveExample = "# Serve the API on localhost\n" +
+		"cidr serve\n" +
+		"\n" +
+		"# Serve the API on all interfaces, with a smaller request size limit\n" +
+		"cidr serve --listen :8080 --max-request-bytes 65536\n" +
+		"\n" +
+		"# Explain a CIDR range through the API\n" +
+		"curl -s -H 'Content-Type: application/json' -d '{\"network\": \"10.0.0.0/16\"}' http://localhost:8080/v1/explain"
+
+	// serveShutdownTimeout is how long in-flight requests are given to finish on shutdown.
+	serveShutdownTimeout = 10 * time.Second
+)
+
+var (
+	serveListen          string
+	serveMaxRequestBytes int64
+	serveMaxNetworks     int
+
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serves the CIDR operations as a JSON HTTP API",
+		Long: "Serves explain, count, contains, overlaps, divide, set, utilization and validate as JSON endpoints\n" +
+			"under /v1. Requests are POSTed as JSON, the API is described by the OpenAPI document at /openapi.json.\n" +
+			"The server shuts down gracefully on SIGINT or SIGTERM.",
+		Example: serveExample,
+		Args:    usageArgs("serve does not take arguments", cobra.NoArgs),
+		RunE:    executeServe,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveListen, "listen", "localhost:8080", "address to listen on")
+	serveCmd.Flags().Int64Var(&serveMaxRequestBytes, "max-request-bytes", server.DefaultMaxRequestBytes, "maximum size of a request body")
+	serveCmd.Flags().IntVar(&serveMaxNetworks, "max-networks", server.DefaultMaxNetworks, "maximum number of networks in a response")
+}
+
+func executeServe(cmd *cobra.Command, args []string) error {
+	if serveMaxRequestBytes <= 0 || serveMaxNetworks <= 0 {
+		return usageError(errors.New("limits must be positive"))
+	}
+
+	listener, err := net.Listen("tcp", serveListen)
+	if err != nil {
+		return usageError(err)
+	}
+	srv := &http.Server{
+		Handler: server.NewHandler(server.Options{
+			MaxRequestBytes: serveMaxRequestBytes,
+			MaxNetworks:     serveMaxNetworks,
+			Unmap:           unmap,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(listener)
+	}()
+	fmt.Fprintf(os.Stderr, "Listening on http://%s\n", listener.Addr())
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
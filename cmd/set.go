@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	setExample = "# Aggregate a list of CIDR ranges into the smallest list that covers the same addresses\n" +
+		"cidr set union networks.txt\n" +
+		"\n" +
+		"# Which of our allowed ranges are also in the vendor's list\n" +
+		"cidr set intersect allowed.txt vendor.txt\n" +
+		"\n" +
+		"# Everything in A but not in B, with A read from stdin\n" +
+		"cat a.txt | cidr set diff - b.txt\n" +
+		"\n" +
+		"# Everything that is in exactly one of both lists\n" +
+		"cidr set xor a.txt b.txt"
+)
+
+var (
+	setCmd = &cobra.Command{
+		Use:   "set",
+		Short: "Performs set operations on lists of CIDR ranges",
+		Long: "Performs set operations on lists of CIDR ranges, one CIDR range or IP address per line.\n" +
+			"Lists are read from files, or from stdin when the file is '-'. The result is printed as the\n" +
+			"smallest list of CIDR ranges that covers it, IPv4 before IPv6.",
+		Example: setExample,
+	}
+
+	setUnionCmd = &cobra.Command{
+		Use:   "union <file>...",
+		Short: "Prints the addresses that are in any of the lists",
+		Args:  usageArgs("provide at least 1 file with CIDR ranges", cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSetOperation(args, (*core.IPSet).Union)
+		},
+	}
+
+	setIntersectCmd = &cobra.Command{
+		Use:   "intersect <file> <file>...",
+		Short: "Prints the addresses that are in all of the lists",
+		Args:  usageArgs("provide at least 2 files with CIDR ranges", cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSetOperation(args, (*core.IPSet).Intersection)
+		},
+	}
+
+	setDiffCmd = &cobra.Command{
+		Use:   "diff <file> <file>...",
+		Short: "Prints the addresses that are in the first list, but in none of the other lists",
+		Args:  usageArgs("provide at least 2 files with CIDR ranges", cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSetOperation(args, (*core.IPSet).Difference)
+		},
+	}
+
+	setXorCmd = &cobra.Command{
+		Use:   "xor <file> <file>...",
+		Short: "Prints the addresses that are in an odd number of the lists",
+		Args:  usageArgs("provide at least 2 files with CIDR ranges", cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSetOperation(args, (*core.IPSet).SymmetricDifference)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	setCmd.AddCommand(setUnionCmd, setIntersectCmd, setDiffCmd, setXorCmd)
+	addInputFormatFlag(setCmd.PersistentFlags())
+}
+
+// executeSetOperation reads the lists from the named files and folds them into a single set
+// using the given operation, from left to right.
+func executeSetOperation(names []string, operation func(a, b *core.IPSet) *core.IPSet) error {
+	var result *core.IPSet
+	for _, name := range names {
+		networks, err := readNetworks(name)
+		if err != nil {
+			return err
+		}
+		set := core.NewIPSet(networks...)
+		if result == nil {
+			result = set
+			continue
+		}
+		result = operation(result, set)
+	}
+
+	for _, network := range result.Networks() {
+		fmt.Println(core.FormatNetwork(network))
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/shell"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const (
+	shellExample = "# Start an interactive session\n" +
+		"cidr shell\n" +
+		"\n" +
+		"# Run the commands in a file\n" +
+		"cidr shell < design.cidr"
+
+	shellLong = "Starts an interactive session that runs every command without the 'cidr' prefix.\n" +
+		"\n" +
+		"Besides the commands, a session understands:\n" +
+		"  $name = <words>...       assign words to a variable, '$name' expands to them\n" +
+		"  unset <name>...          remove variables\n" +
+		"  vars                     print all variables\n" +
+		"  ws                       print the working set, '$ws' expands to its prefixes\n" +
+		"  ws add <prefix>...       add prefixes to the working set\n" +
+		"  ws remove <prefix>...    remove prefixes from the working set\n" +
+		"  ws intersect <prefix>... only keep the addresses that are also in the prefixes\n" +
+		"  ws xor <prefix>...       keep the addresses that are in either, but not in both\n" +
+		"  ws clear                 empty the working set\n" +
+		"  exit, quit               end the session\n" +
+		"\n" +
+		"Press tab to complete commands, variables and the prefixes used earlier in the session,\n" +
+		"and use the arrow keys to edit the line and browse the history."
+
+	shellPrompt = "cidr> "
+)
+
+// shellBuiltins are the words that are handled by the shell itself instead of a command.
+var shellBuiltins = map[string][]string{
+	"exit":  nil,
+	"quit":  nil,
+	"vars":  nil,
+	"unset": nil,
+	"ws":    {"add", "remove", "intersect", "xor", "clear"},
+}
+
+var shellCmd = &cobra.Command{
+	Use:     "shell",
+	Short:   "Starts an interactive session",
+	Long:    shellLong,
+	Example: shellExample,
+	Args:    usageArgs("the shell does not take arguments", cobra.NoArgs),
+	RunE:    executeShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+// shellUnmap keeps --unmap, when given to the shell itself, for every command in the session.
+var shellUnmap bool
+
+// lineReader reads the lines of a session, one at a time.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+func executeShell(cmd *cobra.Command, args []string) error {
+	session := shell.NewSession(shellCommands())
+	shellUnmap = unmap
+
+	var reader lineReader
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		reader = newTerminalReader(fd, session)
+	} else {
+		reader = &scannerReader{bufio.NewScanner(os.Stdin)}
+	}
+
+	for {
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if done := runShellLine(session, line); done {
+			return nil
+		}
+	}
+}
+
+// runShellLine runs a single line of a session and reports whether the session has ended.
+// Errors are printed, but never end the session.
+func runShellLine(session *shell.Session, line string) bool {
+	words, err := expandShellLine(session, line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return false
+	}
+	if len(words) == 0 {
+		return false
+	}
+	session.Remember(words)
+	return runShellCommand(session, words)
+}
+
+// expandShellLine splits a line into words and expands the variables in them. Assignments are
+// carried out right away, they and comments result in no words.
+func expandShellLine(session *shell.Session, line string) ([]string, error) {
+	words, err := shell.Split(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+		return nil, nil
+	}
+
+	if name, values, ok := shell.ParseAssignment(words); ok {
+		values, err := session.Expand(values)
+		if err != nil {
+			return nil, err
+		}
+		return nil, session.Assign(name, values)
+	}
+	return session.Expand(words)
+}
+
+// runShellCommand runs a built-in command of the shell or a cidr command, and reports whether the session has ended.
+func runShellCommand(session *shell.Session, words []string) bool {
+	switch words[0] {
+	case "exit", "quit":
+		return true
+	case "vars":
+		for _, name := range session.Variables() {
+			values, _ := session.Lookup(name)
+			fmt.Printf("%s = %s\n", color.BlueString("$"+name), strings.Join(values, " "))
+		}
+	case "unset":
+		for _, name := range words[1:] {
+			session.Unset(strings.TrimPrefix(name, "$"))
+		}
+	case "ws":
+		if err := runWorkingSet(session, words[1:]); err != nil {
+			handleError(nil, err)
+		}
+	case "shell":
+		fmt.Fprintln(os.Stderr, "error: already in a shell")
+	default:
+		resetFlags(rootCmd)
+		unmap = shellUnmap
+		rootCmd.SetArgs(words)
+		if cmd, err := rootCmd.ExecuteC(); err != nil {
+			handleError(cmd, err)
+		}
+	}
+	return false
+}
+
+// runWorkingSet updates or prints the working set of a session.
+func runWorkingSet(session *shell.Session, args []string) error {
+	if len(args) == 0 {
+		for _, network := range session.WorkingSet().Networks() {
+			fmt.Println(core.FormatNetwork(network))
+		}
+		return nil
+	}
+
+	operations := map[string]func(a, b *core.IPSet) *core.IPSet{
+		"add":       (*core.IPSet).Union,
+		"remove":    (*core.IPSet).Difference,
+		"intersect": (*core.IPSet).Intersection,
+		"xor":       (*core.IPSet).SymmetricDifference,
+	}
+	if args[0] == "clear" {
+		session.SetWorkingSet(core.NewIPSet())
+		return nil
+	}
+	operation, ok := operations[args[0]]
+	if !ok {
+		return usageError(fmt.Errorf("unknown working set operation: %s", args[0]))
+	}
+
+	set := core.NewIPSet()
+	for _, arg := range args[1:] {
+		network, err := parseNetwork(arg)
+		if err != nil {
+			return err
+		}
+		set = set.Union(core.NewIPSet(network))
+	}
+	session.SetWorkingSet(operation(session.WorkingSet(), set))
+	return nil
+}
+
+// shellCommands returns the commands and builtins that can be completed in a session.
+func shellCommands() map[string][]string {
+	commands := make(map[string][]string)
+	for name, subcommands := range shellBuiltins {
+		commands[name] = subcommands
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "shell" || cmd.Hidden {
+			continue
+		}
+		var subcommands []string
+		for _, sub := range cmd.Commands() {
+			subcommands = append(subcommands, sub.Name())
+		}
+		commands[cmd.Name()] = subcommands
+	}
+	return commands
+}
+
+// resetFlags restores the flags of cmd and its subcommands to their defaults, so that
+// flags given to a command in a session do not carry over to the next line.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// terminalReader reads lines from a terminal with line editing, history and tab completion.
+type terminalReader struct {
+	fd       int
+	terminal *term.Terminal
+}
+
+func newTerminalReader(fd int, session *shell.Session) *terminalReader {
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	terminal.AutoCompleteCallback = session.Complete
+	return &terminalReader{fd: fd, terminal: terminal}
+}
+
+// ReadLine puts the terminal in raw mode while a line is edited, and restores it before
+// the line is run so the output of commands is not affected.
+func (r *terminalReader) ReadLine() (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(r.fd, state)
+	if width, height, err := term.GetSize(r.fd); err == nil && width > 0 {
+		_ = r.terminal.SetSize(width, height)
+	}
+	return r.terminal.ReadLine()
+}
+
+// scannerReader reads lines from a file or pipe, without a prompt.
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *scannerReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ulaExample = "# Generate a random unique local /48 for a new site\n" +
+		"cidr ula\n" +
+		"\n" +
+		"# Generate the /48 with the algorithm of RFC 4193, from a MAC address and the current time\n" +
+		"cidr ula --mac 00:1a:2b:3c:4d:5e\n" +
+		"\n" +
+		"# Generate the same /48 for a lab on every run, and make sure no other site uses it\n" +
+		"cidr ula --seed lab-amsterdam --existing ulas.txt"
+)
+
+// maxULASubnets is the number of /64 subnets in a /48.
+const maxULASubnets = 1 << 16
+
+// maxULAAttempts bounds the number of random Global IDs that are tried when they collide with existing prefixes.
+const maxULAAttempts = 100
+
+var (
+	ulaMAC          string
+	ulaTime         string
+	ulaSeed         string
+	ulaExistingFile string
+	ulaSubnets      int
+
+	ulaCmd = &cobra.Command{
+		Use:   "ula",
+		Short: "Generates a unique local IPv6 prefix as described in RFC 4193",
+		Long: "Generates a unique local IPv6 /48 in fd00::/8 with a pseudo-random Global ID as described in RFC 4193.\n" +
+			"With --mac the Global ID is computed with the algorithm of RFC 4193 section 3.2.2 from the MAC address\n" +
+			"or EUI-64 and a timestamp, with --seed it is derived from the seed, and otherwise it is picked at random.\n" +
+			"When the prefix overlaps with one of the --existing prefixes the command exits with status 1, except for\n" +
+			"random prefixes, which are picked again.",
+		Example: ulaExample,
+		Args:    usageArgs("ula takes no arguments", cobra.NoArgs),
+		RunE:    executeULA,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(ulaCmd)
+	ulaCmd.Flags().StringVar(&ulaMAC, "mac", "", "MAC address or EUI-64 to compute the Global ID from")
+	ulaCmd.Flags().StringVar(&ulaTime, "time", "", "timestamp in RFC 3339 format to compute the Global ID from (default: now)")
+	ulaCmd.Flags().StringVar(&ulaSeed, "seed", "", "seed to derive the Global ID from")
+	ulaCmd.Flags().StringVar(&ulaExistingFile, "existing", "", "file with the unique local prefixes in use, '-' for stdin")
+	ulaCmd.Flags().IntVar(&ulaSubnets, "subnets", 4, "number of example /64 subnets to print")
+	ulaCmd.MarkFlagsMutuallyExclusive("mac", "seed")
+	addInputFormatFlag(ulaCmd.Flags())
+}
+
+func executeULA(cmd *cobra.Command, args []string) error {
+	if ulaTime != "" && ulaMAC == "" {
+		return usageError(errors.New("--time can only be used together with --mac"))
+	}
+	if ulaSubnets < 0 || ulaSubnets > maxULASubnets {
+		return usageError(fmt.Errorf("subnets must be between 0 and %d, got %d", maxULASubnets, ulaSubnets))
+	}
+
+	var existing []*net.IPNet
+	if ulaExistingFile != "" {
+		fileNetworks, err := readNetworks(ulaExistingFile)
+		if err != nil {
+			return err
+		}
+		existing = fileNetworks
+	}
+
+	globalID, err := getULAGlobalID(existing)
+	if err != nil {
+		return err
+	}
+	prefix := core.GetULAPrefix(globalID)
+	printULAPrefix(prefix, globalID)
+
+	collisions := getCollisions(prefix, existing)
+	if len(collisions) == 0 {
+		return nil
+	}
+	fmt.Println()
+	fmt.Println(color.RedString("Collides With:"))
+	for _, network := range collisions {
+		fmt.Printf("  %s\n", core.FormatNetwork(network))
+	}
+	return errFalse
+}
+
+// getULAGlobalID returns the Global ID from the MAC address and time, from the seed, or picks a random
+// Global ID that does not collide with the existing prefixes.
+func getULAGlobalID(existing []*net.IPNet) ([]byte, error) {
+	switch {
+	case ulaMAC != "":
+		eui64, err := core.ParseEUI64(ulaMAC)
+		if err != nil {
+			return nil, inputError(err)
+		}
+		t := time.Now()
+		if ulaTime != "" {
+			t, err = time.Parse(time.RFC3339Nano, ulaTime)
+			if err != nil {
+				return nil, inputError(fmt.Errorf("invalid time: %s", ulaTime))
+			}
+		}
+		return core.GetULAGlobalID(t, eui64), nil
+	case ulaSeed != "":
+		return core.GetULAGlobalIDFromSeed(ulaSeed), nil
+	}
+
+	// Random Global IDs can simply be picked again when they collide.
+	globalID := make([]byte, core.ULAGlobalIDLength)
+	for attempt := 0; ; attempt++ {
+		if _, err := rand.Read(globalID); err != nil {
+			return nil, err
+		}
+		if len(getCollisions(core.GetULAPrefix(globalID), existing)) == 0 {
+			return globalID, nil
+		}
+		if attempt == maxULAAttempts {
+			return nil, inputError(fmt.Errorf("%w: no free unique local prefix found", core.ErrInsufficientAddressSpace))
+		}
+	}
+}
+
+// printULAPrefix prints the prefix with its Global ID, its details and the first subnets.
+func printULAPrefix(prefix *net.IPNet, globalID []byte) {
+	fmt.Printf(color.BlueString("ULA Prefix:\t\t ")+"%s\n", core.FormatNetwork(prefix))
+	fmt.Printf(color.BlueString("Global ID:\t\t ")+"%s\n", hex.EncodeToString(globalID))
+	explain(core.GetNetworkDetails(prefix))
+
+	if ulaSubnets > 0 {
+		fmt.Println()
+		fmt.Println(color.BlueString("Example Subnets:"))
+		for i := range ulaSubnets {
+			fmt.Printf("  %s\n", core.FormatNetwork(getULASubnet(prefix, i)))
+		}
+	}
+}
+
+// getULASubnet returns the n-th /64 subnet of a unique local /48, n being the Subnet ID of RFC 4193.
+func getULASubnet(prefix *net.IPNet, n int) *net.IPNet {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, prefix.IP)
+	binary.BigEndian.PutUint16(ip[6:8], uint16(n))
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(64, 128)}
+}
+
+// getCollisions returns the networks that overlap with the prefix.
+func getCollisions(prefix *net.IPNet, networks []*net.IPNet) []*net.IPNet {
+	var collisions []*net.IPNet
+	for _, network := range networks {
+		if core.Overlaps(prefix, network) {
+			collisions = append(collisions, network)
+		}
+	}
+	return collisions
+}
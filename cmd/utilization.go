@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	utilizationExample = "# Report the utilization of a network by the given allocations\n" +
+		"cidr utilization 10.0.0.0/16 10.0.0.0/20 10.0.16.0/21 10.0.64.0/20\n" +
+		"\n" +
+		"# Read the allocations from a file, and list the free blocks up to /24\n" +
+		"cidr utilization --allocations subnets.txt --max-prefix-length 24 10.0.0.0/16\n" +
+		"\n" +
+		"# Report the utilization of an IPv6 pool as JSON\n" +
+		"cidr utilization --output json 2001:db8::/32 2001:db8::/33"
+)
+
+var (
+	utilizationAllocationsFile string
+	utilizationMaxPrefixLength int
+	utilizationOutput          string
+
+	utilizationCmd = &cobra.Command{
+		Use:   "utilization <network> [allocation...]",
+		Short: "Reports how much of a network is allocated and which space is still free",
+		Long: "Reports how much of a network is allocated and which space is still free, as a list of CIDR ranges.\n" +
+			"It also reports how many aligned blocks of each prefix length still fit in the free space, and a\n" +
+			"fragmentation score: 0% when all free space is a single block, approaching 100% as it is split up.",
+		Example: utilizationExample,
+		Args:    usageArgs("provide a CIDR range and its allocations", cobra.MinimumNArgs(1)),
+		RunE:    executeUtilization,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(utilizationCmd)
+	utilizationCmd.Flags().StringVar(&utilizationAllocationsFile, "allocations", "", "file with the allocated CIDR ranges, '-' for stdin")
+	utilizationCmd.Flags().IntVar(&utilizationMaxPrefixLength, "max-prefix-length", 0, "longest prefix length to report free blocks for (default /32 for IPv4, /64 for IPv6)")
+	utilizationCmd.Flags().StringVarP(&utilizationOutput, "output", "o", "text", "output format, one of text or json")
+	addInputFormatFlag(utilizationCmd.Flags())
+}
+
+// freeBlockCount is the number of aligned free blocks of a prefix length.
+type freeBlockCount struct {
+	PrefixLength int    `json:"prefixLength"`
+	Count        string `json:"count"`
+}
+
+type utilizationResult struct {
+	Network              string           `json:"network"`
+	Addresses            string           `json:"addresses"`
+	Allocated            string           `json:"allocated"`
+	Free                 string           `json:"free"`
+	UsedPercentage       float64          `json:"usedPercentage"`
+	LargestFreeNetwork   string           `json:"largestFreeNetwork,omitempty"`
+	FragmentationPercent float64          `json:"fragmentationPercentage"`
+	FreeNetworks         []string         `json:"freeNetworks"`
+	FreeBlocks           []freeBlockCount `json:"freeBlocks"`
+}
+
+func executeUtilization(cmd *cobra.Command, args []string) error {
+	if utilizationOutput != "text" && utilizationOutput != "json" {
+		return usageError(fmt.Errorf("unknown output format: %s", utilizationOutput))
+	}
+	network, err := parseNetwork(args[0])
+	if err != nil {
+		return err
+	}
+
+	allocations := make([]*net.IPNet, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		allocation, err := parsePrefix(arg)
+		if err != nil {
+			return err
+		}
+		allocations = append(allocations, allocation)
+	}
+	if utilizationAllocationsFile != "" {
+		fileAllocations, err := readNetworks(utilizationAllocationsFile)
+		if err != nil {
+			return err
+		}
+		allocations = append(allocations, fileAllocations...)
+	}
+
+	utilization, err := core.GetUtilization(network, allocations)
+	if err != nil {
+		return inputError(err)
+	}
+	result := getUtilizationResult(utilization)
+
+	if utilizationOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	printUtilization(result)
+	return nil
+}
+
+func getUtilizationResult(utilization *core.Utilization) *utilizationResult {
+	result := &utilizationResult{
+		Network:              core.FormatNetwork(utilization.Network),
+		Addresses:            utilization.Total.String(),
+		Allocated:            utilization.Allocated.String(),
+		Free:                 utilization.Free.String(),
+		UsedPercentage:       utilization.GetUsedPercentage(),
+		FragmentationPercent: utilization.GetFragmentation(),
+		FreeNetworks:         make([]string, len(utilization.FreeNetworks)),
+		FreeBlocks:           make([]freeBlockCount, 0),
+	}
+	for i, network := range utilization.FreeNetworks {
+		result.FreeNetworks[i] = core.FormatNetwork(network)
+	}
+
+	largest := utilization.GetLargestFreeNetwork()
+	if largest == nil {
+		return result
+	}
+	result.LargestFreeNetwork = core.FormatNetwork(largest)
+
+	// List the free blocks from the largest free block up to the longest prefix length.
+	largestPrefixLength, bits := largest.Mask.Size()
+	maxPrefixLength := utilizationMaxPrefixLength
+	if maxPrefixLength == 0 {
+		maxPrefixLength = 32
+		if !helper.IsIPv4Network(utilization.Network) {
+			maxPrefixLength = max(64, largestPrefixLength)
+		}
+	}
+	for prefixLength := largestPrefixLength; prefixLength <= min(maxPrefixLength, bits); prefixLength++ {
+		result.FreeBlocks = append(result.FreeBlocks, freeBlockCount{
+			PrefixLength: prefixLength,
+			Count:        utilization.GetFreeNetworkCount(prefixLength).String(),
+		})
+	}
+	return result
+}
+
+func printUtilization(result *utilizationResult) {
+	fmt.Printf(color.BlueString("Network:\t\t ")+"%s\n", result.Network)
+	fmt.Printf(color.BlueString("Addresses:\t\t ")+"%s\n", helper.FormatNumber(result.Addresses))
+	fmt.Printf(color.BlueString("Allocated:\t\t ")+"%s (%.2f%%)\n", helper.FormatNumber(result.Allocated), result.UsedPercentage)
+	fmt.Printf(color.BlueString("Free:\t\t\t ")+"%s (%.2f%%)\n", helper.FormatNumber(result.Free), 100-result.UsedPercentage)
+	if result.LargestFreeNetwork == "" {
+		return
+	}
+	fmt.Printf(color.BlueString("Largest Free Block:\t ")+"%s\n", result.LargestFreeNetwork)
+	fmt.Printf(color.BlueString("Fragmentation:\t\t ")+"%.2f%%\n", result.FragmentationPercent)
+
+	fmt.Println()
+	fmt.Println(color.BlueString("Free Networks:"))
+	for _, network := range result.FreeNetworks {
+		fmt.Printf("  %s\n", network)
+	}
+
+	fmt.Println()
+	fmt.Println(color.BlueString("Free Blocks:"))
+	for _, block := range result.FreeBlocks {
+		fmt.Printf("  /%-4d %s\n", block.PrefixLength, helper.FormatNumber(block.Count))
+	}
+}
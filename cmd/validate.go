@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+const (
+	validateExample = "# Lint a file that holds one CIDR range or IP address per line\n" +
+		"cidr validate networks.txt\n" +
+		"\n" +
+		"# Lint prefixes read from stdin\n" +
+		"cat networks.txt | cidr validate -\n" +
+		"\n" +
+		"# Configure the rules and report the findings as SARIF for code scanning\n" +
+		"cidr validate --config cidr-rules.yaml --output sarif networks.txt > cidr.sarif\n" +
+		"\n" +
+		"# Only fail on findings with a severity of warning or higher\n" +
+		"cidr validate --fail-on warning networks.txt"
+)
+
+var (
+	validateConfigFile string
+	validateOutput     string
+	validateFailOn     string
+
+	validateCmd = &cobra.Command{
+		Use:     "validate [file...]",
+		Short:   "Lints lists of CIDR ranges for mistakes such as overlaps, duplicates and host bits set",
+		Long:    validateLong(),
+		Example: validateExample,
+		RunE:    executeValidate,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVar(&validateConfigFile, "config", "", "YAML file that configures the severity and options of the rules")
+	validateCmd.Flags().StringVarP(&validateOutput, "output", "o", "text", "output format, one of text, json or sarif")
+	validateCmd.Flags().StringVar(&validateFailOn, "fail-on", "error", "lowest severity that makes the command exit with 1, one of info, warning, error or off")
+	addInputFormatFlag(validateCmd.Flags())
+}
+
+func validateLong() string {
+	long := "Lints lists of CIDR ranges, one CIDR range or IP address per line. Everything after a '#' is a comment.\n" +
+		"Without files, or with '-', the list is read from stdin.\n" +
+		"\n" +
+		"Rules:\n"
+	for _, rule := range lint.Rules {
+		long += fmt.Sprintf("  %s  %-16s %s (default: %s)\n", rule.ID, rule.Name, rule.Description, rule.DefaultSeverity)
+	}
+	return long
+}
+
+func executeValidate(cmd *cobra.Command, args []string) error {
+	failOn, err := lint.ParseSeverity(validateFailOn)
+	if err != nil {
+		return usageError(err)
+	}
+	if validateOutput != "text" && validateOutput != "json" && validateOutput != "sarif" {
+		return usageError(fmt.Errorf("unknown output format: %s", validateOutput))
+	}
+
+	config := lint.DefaultConfig()
+	if validateConfigFile != "" {
+		config, err = lint.LoadConfig(validateConfigFile)
+		if err != nil {
+			return inputError(err)
+		}
+	}
+
+	if len(args) == 0 {
+		args = []string{input.Stdin}
+	}
+	var lines []input.Line
+	for _, name := range args {
+		fileLines, err := readLines(name)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fileLines...)
+	}
+
+	config.Unmap = config.Unmap || unmap
+	findings := lint.Lint(lines, config)
+	switch validateOutput {
+	case "json":
+		err = lint.WriteJSON(os.Stdout, findings)
+	case "sarif":
+		err = lint.WriteSARIF(os.Stdout, lint.ToolInfo{
+			Name:           "cidr",
+			Version:        version,
+			InformationURI: "https://github.com/bschaatsbergen/cidr",
+		}, lint.Rules, findings)
+	default:
+		err = lint.WriteText(os.Stdout, findings)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range findings {
+		if failOn != lint.SeverityOff && finding.Severity >= failOn {
+			return errFalse
+		}
+	}
+	return nil
+}
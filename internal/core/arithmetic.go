@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// AddressToInt returns the value of the given address as an unsigned integer. A 4-byte address is
+// an IPv4 address, a 16-byte address is an IPv6 address.
+func AddressToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
+}
+
+// IntToAddress returns the address with the given value, an IPv4 address if ipv4 is set and an
+// IPv6 address otherwise. It returns an error wrapping ErrAddressOverflow if the value does not
+// fit in the address family.
+func IntToAddress(i *big.Int, ipv4 bool) (net.IP, error) {
+	length, family := net.IPv6len, "IPv6"
+	if ipv4 {
+		length, family = net.IPv4len, "IPv4"
+	}
+	if i.Sign() < 0 || i.BitLen() > 8*length {
+		return nil, fmt.Errorf("%w: %s is not an %s address", ErrAddressOverflow, i, family)
+	}
+	return bigIntToIP(i, length), nil
+}
+
+// AddToAddress returns the address that comes n addresses after ip, or before ip if n is negative.
+// The result has the address family of ip, an error wrapping ErrAddressOverflow is returned when it
+// would fall outside of the address family.
+func AddToAddress(ip net.IP, n *big.Int) (net.IP, error) {
+	ipv4 := len(ip) == net.IPv4len
+	sum := new(big.Int).Add(AddressToInt(ip), n)
+	result, err := IntToAddress(sum, ipv4)
+	if err != nil {
+		operator, family := "+", "IPv6"
+		if n.Sign() < 0 {
+			operator = "-"
+		}
+		if ipv4 {
+			family = "IPv4"
+		}
+		return nil, fmt.Errorf("%w: %s %s %s is outside of the %s address space", ErrAddressOverflow, FormatAddress(ip), operator, new(big.Int).Abs(n), family)
+	}
+	return result, nil
+}
+
+// GetAddressDistance returns the number of addresses from a to b, which is negative when b comes
+// before a. It returns ErrAddressFamilyMismatch if a and b are of different address families.
+func GetAddressDistance(a, b net.IP) (*big.Int, error) {
+	if len(a) != len(b) {
+		return nil, ErrAddressFamilyMismatch
+	}
+	return new(big.Int).Sub(AddressToInt(b), AddressToInt(a)), nil
+}
+
+// GetNthAddress returns the address at index n of the network, where 0 is the base address.
+// A negative n counts back from the last address, which is at index -1. An error wrapping
+// ErrOutsideNetwork is returned when the index falls outside of the network.
+func GetNthAddress(network *net.IPNet, n *big.Int) (net.IP, error) {
+	r := GetNetworkRange(network)
+	offset := new(big.Int).Set(n)
+	if n.Sign() < 0 {
+		offset.Add(offset, r.GetAddressCount())
+	}
+	if offset.Sign() < 0 || offset.Cmp(r.GetAddressCount()) >= 0 {
+		return nil, fmt.Errorf("address %s is %w %s, which holds %s addresses", n, ErrOutsideNetwork, FormatNetwork(network), r.GetAddressCount())
+	}
+	return AddToAddress(r.First, offset)
+}
@@ -0,0 +1,191 @@
+package core_test
+
+import (
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseAddress(t *testing.T, address string) net.IP {
+	t.Helper()
+	ip, err := core.ParseAddress(address)
+	require.NoError(t, err)
+	return ip
+}
+
+func mustParseInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	i, ok := new(big.Int).SetString(s, 0)
+	require.True(t, ok, "invalid integer %s", s)
+	return i
+}
+
+func TestAddToAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		n        string
+		expected string
+		overflow bool
+	}{
+		{
+			name:     "Add to an IPv4 address across an octet",
+			address:  "10.0.0.255",
+			n:        "1",
+			expected: "10.0.1.0",
+		},
+		{
+			name:     "Subtract from an IPv4 address across an octet",
+			address:  "10.0.1.0",
+			n:        "-1",
+			expected: "10.0.0.255",
+		},
+		{
+			name:     "Add to an IPv6 address across 64 bits",
+			address:  "2001:db8::ffff:ffff:ffff:ffff",
+			n:        "1",
+			expected: "2001:db8:0:1::",
+		},
+		{
+			name:     "Add more than 64 bits to an IPv6 address",
+			address:  "::",
+			n:        "0x10000000000000000",
+			expected: "0:0:0:1::",
+		},
+		{
+			name:     "Add to an IPv4-mapped IPv6 address",
+			address:  "::ffff:255.255.255.255",
+			n:        "1",
+			expected: "::1:0:0:0",
+		},
+		{
+			name:     "Overflow the IPv4 address space",
+			address:  "255.255.255.255",
+			n:        "1",
+			overflow: true,
+		},
+		{
+			name:     "Underflow the IPv4 address space",
+			address:  "0.0.0.0",
+			n:        "-1",
+			overflow: true,
+		},
+		{
+			name:     "Overflow the IPv6 address space",
+			address:  "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+			n:        "1",
+			overflow: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := core.AddToAddress(mustParseAddress(t, tt.address), mustParseInt(t, tt.n))
+			if tt.overflow {
+				assert.ErrorIs(t, err, core.ErrAddressOverflow)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, core.FormatAddress(ip))
+		})
+	}
+}
+
+func TestGetAddressDistance(t *testing.T) {
+	distance, err := core.GetAddressDistance(mustParseAddress(t, "10.0.0.5"), mustParseAddress(t, "10.0.3.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(770), distance)
+
+	distance, err = core.GetAddressDistance(mustParseAddress(t, "ffff::"), mustParseAddress(t, "::"))
+	assert.NoError(t, err)
+	assert.Equal(t, mustParseInt(t, "-0xffff0000000000000000000000000000"), distance)
+
+	_, err = core.GetAddressDistance(mustParseAddress(t, "10.0.0.1"), mustParseAddress(t, "::ffff:10.0.0.1"))
+	assert.ErrorIs(t, err, core.ErrAddressFamilyMismatch)
+}
+
+func TestGetNthAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		n        string
+		expected string
+		outside  bool
+	}{
+		{
+			name:     "First address of an IPv4 network",
+			network:  "10.0.0.0/16",
+			n:        "0",
+			expected: "10.0.0.0",
+		},
+		{
+			name:     "Address 1000 of an IPv4 network",
+			network:  "10.0.0.0/16",
+			n:        "1000",
+			expected: "10.0.3.232",
+		},
+		{
+			name:     "Last address of an IPv6 network",
+			network:  "2001:db8::/64",
+			n:        "-1",
+			expected: "2001:db8::ffff:ffff:ffff:ffff",
+		},
+		{
+			name:     "First address of an IPv6 network counting back",
+			network:  "2001:db8::/126",
+			n:        "-4",
+			expected: "2001:db8::",
+		},
+		{
+			name:    "Past the end of an IPv4 network",
+			network: "10.0.0.0/16",
+			n:       "65536",
+			outside: true,
+		},
+		{
+			name:    "Before the start of an IPv6 network",
+			network: "2001:db8::/126",
+			n:       "-5",
+			outside: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			require.NoError(t, err)
+			ip, err := core.GetNthAddress(network, mustParseInt(t, tt.n))
+			if tt.outside {
+				assert.ErrorIs(t, err, core.ErrOutsideNetwork)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, core.FormatAddress(ip))
+		})
+	}
+}
+
+func TestIntToAddress(t *testing.T) {
+	ip, err := core.IntToAddress(big.NewInt(167772161), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", core.FormatAddress(ip))
+	assert.Equal(t, big.NewInt(167772161), core.AddressToInt(ip))
+
+	ip, err = core.IntToAddress(big.NewInt(1), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "::1", core.FormatAddress(ip))
+
+	last := mustParseInt(t, "340282366920938463463374607431768211455")
+	ip, err = core.IntToAddress(last, false)
+	assert.NoError(t, err)
+	assert.Equal(t, last, core.AddressToInt(ip))
+
+	_, err = core.IntToAddress(big.NewInt(1<<32), true)
+	assert.ErrorIs(t, err, core.ErrAddressOverflow)
+	_, err = core.IntToAddress(new(big.Int).Add(last, big.NewInt(1)), false)
+	assert.ErrorIs(t, err, core.ErrAddressOverflow)
+	_, err = core.IntToAddress(big.NewInt(-1), false)
+	assert.ErrorIs(t, err, core.ErrAddressOverflow)
+}
@@ -1,21 +1,81 @@
 package core
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 	"net"
+	"net/netip"
+	"strings"
 
 	"github.com/bschaatsbergen/cidr/internal/helper"
 )
 
 // ParseCIDR parses the given CIDR notation string and returns the corresponding IP network.
+// IPv4 networks have a 4-byte mask, IPv6 networks, including IPv4-mapped ones such as
+// ::ffff:10.0.0.0/104, have a 16-byte mask. Use Unmap to treat IPv4-mapped networks as IPv4.
+// The returned error is a *ParseError wrapping ErrInvalidCIDR.
 func ParseCIDR(network string) (*net.IPNet, error) {
 	_, ip, err := net.ParseCIDR(network)
 	if err != nil {
-		return nil, err
+		return nil, &ParseError{Input: network, Err: ErrInvalidCIDR}
+	}
+	return ip, nil
+}
+
+// ParseAddress parses the given IP address string and returns the corresponding IP address.
+// IPv4 addresses are returned in their 4-byte form, IPv6 addresses, including IPv4-mapped ones
+// such as ::ffff:10.0.0.1, in their 16-byte form. Use UnmapAddress to treat IPv4-mapped addresses as IPv4.
+// The returned error is a *ParseError wrapping ErrInvalidAddress.
+func ParseAddress(address string) (net.IP, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, &ParseError{Input: address, Err: ErrInvalidAddress}
+	}
+	if !strings.Contains(address, ":") {
+		ip = ip.To4()
+	}
+	return ip, nil
+}
+
+// Unmap returns the IPv4 network that an IPv4-mapped IPv6 network such as ::ffff:10.0.0.0/104
+// stands for, 10.0.0.0/8 in this case. Any other network is returned as is.
+func Unmap(network *net.IPNet) *net.IPNet {
+	ones, bits := network.Mask.Size()
+	ip := network.IP.To4()
+	if bits != 8*net.IPv6len || ones < 8*(net.IPv6len-net.IPv4len) || ip == nil {
+		return network
 	}
-	return ip, err
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones-8*(net.IPv6len-net.IPv4len), 8*net.IPv4len)}
+}
+
+// UnmapAddress returns the IPv4 address that an IPv4-mapped IPv6 address such as ::ffff:10.0.0.1
+// stands for, 10.0.0.1 in this case. Any other address is returned as is.
+func UnmapAddress(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+// FormatNetwork returns the CIDR notation of the given network. Unlike net.IPNet.String, it keeps
+// IPv4-mapped IPv6 networks in IPv6 notation, such as ::ffff:10.0.0.0/104.
+func FormatNetwork(network *net.IPNet) string {
+	if !helper.IsIPv6Network(network) {
+		return network.String()
+	}
+	ones, _ := network.Mask.Size()
+	addr, _ := netip.AddrFromSlice(network.IP.To16())
+	return netip.PrefixFrom(addr, ones).String()
+}
+
+// FormatAddress returns the notation of the given address. Unlike net.IP.String, it keeps 16-byte
+// IPv4-mapped addresses in IPv6 notation, such as ::ffff:10.0.0.1.
+func FormatAddress(ip net.IP) string {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ip.String()
+	}
+	return addr.String()
 }
 
 // GetAddressCount returns the total number of addresses in the given IP network.
@@ -24,7 +84,7 @@ func GetAddressCount(network *net.IPNet) *big.Int {
 	prefixLen, bits := network.Mask.Size()
 
 	// Handle specific cases for IPv4 prefix lengths.
-	if network.IP.To4() != nil {
+	if helper.IsIPv4Network(network) {
 		switch prefixLen {
 		case 32:
 			// A /32 prefix contains a single address.
@@ -46,7 +106,7 @@ func GetHostAddressCount(network *net.IPNet) *big.Int {
 	prefixLen, bits := network.Mask.Size()
 
 	// Handle edge cases for specific IPv4 prefix lengths.
-	if network.IP.To4() != nil {
+	if helper.IsIPv4Network(network) {
 		switch prefixLen {
 		case 32:
 			// Single IP address for /32 (e.g., point-to-point link).
@@ -64,14 +124,59 @@ func GetHostAddressCount(network *net.IPNet) *big.Int {
 
 // ContainsAddress checks if the given IP network contains the specified IP address.
 // It returns true if the address is within the network, otherwise false.
+// An address never belongs to a network of the other address family: a 4-byte address is IPv4 and
+// a 16-byte address is IPv6, even if it is IPv4-mapped. A network's family follows from its mask.
 func ContainsAddress(network *net.IPNet, ip net.IP) bool {
-	return network.Contains(ip)
+	if len(ip) != len(network.Mask) {
+		return false
+	}
+	r := GetNetworkRange(network)
+	return compareIP(r.First, ip) <= 0 && compareIP(ip, r.Last) <= 0
+}
+
+// ContainsNetwork checks if the given IP network fully contains the specified subnet.
+// It returns true if every address of the subnet is within the network, otherwise false.
+// Networks of different address families never contain each other.
+func ContainsNetwork(network, subnet *net.IPNet) bool {
+	ones, bits := network.Mask.Size()
+	subnetOnes, subnetBits := subnet.Mask.Size()
+	return bits == subnetBits && ones <= subnetOnes && ContainsAddress(network, networkIP(subnet))
+}
+
+// Containment describes how much of a subnet is part of a network.
+type Containment int
+
+const (
+	// NotContained means that the subnet and the network have no addresses in common.
+	NotContained Containment = iota
+	// PartiallyContained means that only some addresses of the subnet are part of the network.
+	PartiallyContained
+	// FullyContained means that every address of the subnet is part of the network.
+	FullyContained
+)
+
+// GetContainment returns how much of the given subnet is part of the network.
+func GetContainment(network, subnet *net.IPNet) Containment {
+	switch {
+	case ContainsNetwork(network, subnet):
+		return FullyContained
+	case ContainsNetwork(subnet, network):
+		return PartiallyContained
+	default:
+		return NotContained
+	}
 }
 
 // Overlaps checks if there is an overlap between two IP networks.
 // It returns true if there is any overlap, otherwise false.
+// Networks of different address families never overlap.
 func Overlaps(network1, network2 *net.IPNet) bool {
-	return network1.Contains(network2.IP) || network2.Contains(network1.IP)
+	if len(network1.Mask) != len(network2.Mask) {
+		return false
+	}
+	r1 := GetNetworkRange(network1)
+	r2 := GetNetworkRange(network2)
+	return compareIP(r1.First, r2.Last) <= 0 && compareIP(r2.First, r1.Last) <= 0
 }
 
 // GetNetmask retrieves the netmask associated with the provided IP network.
@@ -99,32 +204,26 @@ func GetBaseAddress(network *net.IPNet) net.IP {
 // GetFirstUsableIPAddress returns the first usable IP address in the given IP network.
 func GetFirstUsableIPAddress(network *net.IPNet) (net.IP, error) {
 	// If it's an IPv6 network
-	if network.IP.To4() == nil {
+	if !helper.IsIPv4Network(network) {
 		ones, bits := network.Mask.Size()
 		if ones == bits {
-			return nil, errors.New(IPv6NetworkHasNoFirstUsableAddressError)
+			return nil, ErrIPv6NetworkHasNoFirstUsableAddress
 		}
 
 		// The first address is the first usable address
-		firstIP := make(net.IP, len(network.IP))
-		copy(firstIP, network.IP)
-
-		return firstIP, nil
+		return networkIP(network), nil
 	}
 
 	// If it's an IPv4 network, first handle edge cases
 	switch ones, _ := network.Mask.Size(); ones {
 	case 32:
-		return nil, errors.New(IPv4NetworkHasNoFirstUsableAddressError)
+		return nil, ErrIPv4NetworkHasNoFirstUsableAddress
 	case 31:
 		// For /31 network, the current address is the only usable address
-		firstIP := make(net.IP, len(network.IP))
-		copy(firstIP, network.IP)
-		return firstIP, nil
+		return networkIP(network), nil
 	default:
 		// Add 1 to the network address to get the first usable address
-		ip := make(net.IP, len(network.IP))
-		copy(ip, network.IP)
+		ip := networkIP(network)
 		ip[3]++ // Add 1 to the last octet
 
 		return ip, nil
@@ -134,38 +233,28 @@ func GetFirstUsableIPAddress(network *net.IPNet) (net.IP, error) {
 // GetLastUsableIPAddress returns the last usable IP address in the given IP network.
 func GetLastUsableIPAddress(network *net.IPNet) (net.IP, error) {
 	// If it's an IPv6 network
-	if network.IP.To4() == nil {
+	if !helper.IsIPv4Network(network) {
 		ones, bits := network.Mask.Size()
 		if ones == bits {
-			return nil, errors.New(IPv6NetworkHasNoLastUsableAddressError)
+			return nil, ErrIPv6NetworkHasNoLastUsableAddress
 		}
 
 		// The last address is the last usable address
-		lastIP := make(net.IP, len(network.IP))
-		copy(lastIP, network.IP)
-		for i := range lastIP {
-			lastIP[i] |= ^network.Mask[i]
-		}
-
-		return lastIP, nil
+		return GetNetworkRange(network).Last, nil
 	}
 
 	// If it's an IPv4 network, first handle edge cases
 	switch ones, _ := network.Mask.Size(); ones {
 	case 32:
-		return nil, errors.New(IPv4NetworkHasNoLastUsableAddressError)
+		return nil, ErrIPv4NetworkHasNoLastUsableAddress
 	case 31:
 		// For /31 network, the other address is the last usable address
-		lastIP := make(net.IP, len(network.IP))
-		copy(lastIP, network.IP)
+		lastIP := networkIP(network)
 		lastIP[3] |= 1 // Flip the last bit to get the other address
 		return lastIP, nil
 	default:
 		// Subtract 1 from the broadcast address to get the last usable address
-		ip := make(net.IP, len(network.IP))
-		for i := range ip {
-			ip[i] = network.IP[i] | ^network.Mask[i]
-		}
+		ip := GetNetworkRange(network).Last
 		ip[3]-- // Subtract 1 from the last octet
 
 		return ip, nil
@@ -174,29 +263,24 @@ func GetLastUsableIPAddress(network *net.IPNet) (net.IP, error) {
 
 // GetBroadcastAddress returns the broadcast address of the given IPv4 network, or an error if the IP network is IPv6.
 func GetBroadcastAddress(network *net.IPNet) (net.IP, error) {
-	if network.IP.To4() == nil {
+	if !helper.IsIPv4Network(network) {
 		// IPv6 networks do not have broadcast addresses.
-		return nil, errors.New(IPv6HasNoBroadcastAddressError)
+		return nil, ErrIPv6HasNoBroadcastAddress
 	}
 
 	// Handle edge case for /31 and /32 networks as they have no broadcast address.
 	if prefixLen, _ := network.Mask.Size(); helper.ContainsInt([]int{31, 32}, prefixLen) {
-		return nil, errors.New(IPv4HasNoBroadcastAddressError)
+		return nil, ErrIPv4HasNoBroadcastAddress
 	}
 
-	ip := make(net.IP, len(network.IP))
-	for i := range ip {
-		ip[i] = network.IP[i] | ^network.Mask[i]
-	}
-
-	return ip, nil
+	return GetNetworkRange(network).Last, nil
 }
 
 // GetMaskWithDivisor calculates the subnet mask for the given divisor and address count.
 func GetMaskWithDivisor(divisor int64, addressCount *big.Int, IPv4 bool) (net.IPMask, error) {
 	div := big.NewInt(divisor)
-	if addressCount.Cmp(div) == -1 || div.Cmp(big.NewInt(0)) == 0 {
-		return nil, fmt.Errorf("cannot divide %d addresses into %d divisions", addressCount, div)
+	if addressCount.Cmp(div) == -1 || div.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: cannot divide %d addresses into %d divisions", ErrInvalidDivisor, addressCount, div)
 	}
 
 	addressPartition := new(big.Int).Div(addressCount, div)
@@ -211,11 +295,11 @@ func GetMaskWithDivisor(divisor int64, addressCount *big.Int, IPv4 bool) (net.IP
 	if IPv4 {
 		bits = net.IPv4len * 8
 		if subnetPrefix > 30 {
-			return nil, fmt.Errorf("address Space is insufficient for %d subnets", div)
+			return nil, fmt.Errorf("%w for %d subnets", ErrInsufficientAddressSpace, div)
 		}
 	}
 	if subnetPrefix > 126 {
-		return nil, fmt.Errorf("address Space is insufficient for %d subnets", div)
+		return nil, fmt.Errorf("%w for %d subnets", ErrInsufficientAddressSpace, div)
 	}
 	return net.CIDRMask(bits-subnetPrefix, bits), nil
 }
@@ -227,18 +311,18 @@ func DivideCIDR(network *net.IPNet, divisor int64) ([]net.IPNet, error) {
 	addressCount := GetAddressCount(network)
 	newSubnetMask, err := GetMaskWithDivisor(divisor, addressCount, isIPv4)
 	if err != nil {
-		return nil, fmt.Errorf("%s", err)
+		return nil, err
 	}
 
 	networks := make([]net.IPNet, divisor)
 	nextAddress := new(net.IPNet)
-	nextAddress.IP = network.IP
+	nextAddress.IP = networkIP(network)
 	nextAddress.Mask = newSubnetMask
 	subnetSize := GetAddressCount(nextAddress)
 	for i := int64(0); i < divisor; i++ {
 		networks[i] = *nextAddress
 		ipAsInt := new(big.Int).SetBytes(nextAddress.IP)
-		nextAddress.IP = new(big.Int).Add(ipAsInt, subnetSize).Bytes()
+		nextAddress.IP = bigIntToIP(new(big.Int).Add(ipAsInt, subnetSize), len(nextAddress.IP))
 	}
 	return networks, nil
 }
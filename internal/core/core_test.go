@@ -1,8 +1,10 @@
 package core_test
 
 import (
+	"fmt"
 	"math/big"
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/bschaatsbergen/cidr/internal/core"
@@ -213,13 +215,13 @@ func TestContainsAddress(t *testing.T) {
 		{
 			name:     "IPv4 CIDR that does contain an IPv4 IP",
 			cidr:     IPv4CIDR,
-			ip:       net.ParseIP("10.0.14.5"),
+			ip:       net.ParseIP("10.0.14.5").To4(),
 			contains: true,
 		},
 		{
 			name:     "IPv4 CIDR that does NOT contain an IPv4 IP",
 			cidr:     IPv4CIDR,
-			ip:       net.ParseIP("10.1.55.5"),
+			ip:       net.ParseIP("10.1.55.5").To4(),
 			contains: false,
 		},
 		{
@@ -457,6 +459,7 @@ func TestGetBroadcastAddress(t *testing.T) {
 			broadcastAddress, err := core.GetBroadcastAddress(tt.CIDR)
 			if err != nil {
 				assert.Equal(t, tt.wantErr, true, "Expected error when getting broadcast address, but got none")
+				assert.ErrorIs(t, err, core.ErrNoBroadcastAddress, "Error should match ErrNoBroadcastAddress")
 			} else {
 				assert.Equal(t, tt.expectedBroadcastAddress, broadcastAddress, "Broadcast IP address is not correct")
 			}
@@ -540,6 +543,58 @@ func TestParseCIDR(t *testing.T) {
 				t.Errorf("ParseCIDR() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr {
+				var parseErr *core.ParseError
+				assert.ErrorAs(t, err, &parseErr, "Error should be a ParseError")
+				assert.ErrorIs(t, err, core.ErrInvalidCIDR, "Error should match ErrInvalidCIDR")
+				assert.Equal(t, tt.cidrStr, parseErr.Input, "ParseError should record the input")
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		addressStr string
+		wantErr    bool
+	}{
+		{
+			name:       "Parse a valid IPv4 address",
+			addressStr: "10.0.14.5",
+			wantErr:    false,
+		},
+		{
+			name:       "Parse a valid IPv6 address",
+			addressStr: "2001:db8:1234:1a00::",
+			wantErr:    false,
+		},
+		{
+			name:       "Parse a valid IPv4-mapped IPv6 address",
+			addressStr: "::ffff:10.0.14.5",
+			wantErr:    false,
+		},
+		{
+			name:       "Parse an invalid IPv4 address",
+			addressStr: "356.356.356.356",
+			wantErr:    true,
+		},
+		{
+			name:       "Parse a CIDR range as an address",
+			addressStr: "10.0.0.0/16",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := core.ParseAddress(tt.addressStr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.ErrorIs(t, err, core.ErrInvalidAddress, "Error should match ErrInvalidAddress")
+			}
 		})
 	}
 }
@@ -573,6 +628,20 @@ func TestDivideCIDR(t *testing.T) {
 			expected:  []string{"2001:db8::/34", "2001:db8:4000::/34", "2001:db8:8000::/34"},
 			shouldErr: false,
 		},
+		{
+			name:      "Divide IPv4 CIDR at the start of the address space",
+			cidr:      "0.0.0.0/8",
+			divisor:   4,
+			expected:  []string{"0.0.0.0/10", "0.64.0.0/10", "0.128.0.0/10", "0.192.0.0/10"},
+			shouldErr: false,
+		},
+		{
+			name:      "Divide IPv6 CIDR at the start of the address space",
+			cidr:      "::/120",
+			divisor:   2,
+			expected:  []string{"::/121", "::80/121"},
+			shouldErr: false,
+		},
 		{
 			name:      "Error case: Divisor is zero",
 			cidr:      "10.0.0.0/16",
@@ -580,6 +649,13 @@ func TestDivideCIDR(t *testing.T) {
 			expected:  nil,
 			shouldErr: true,
 		},
+		{
+			name:      "Error case: Divisor is negative",
+			cidr:      "10.0.0.0/16",
+			divisor:   -3,
+			expected:  nil,
+			shouldErr: true,
+		},
 		{
 			name:      "Error case: Cannot divide /128 CIDR",
 			cidr:      "2001:db8::/128",
@@ -609,3 +685,275 @@ func TestDivideCIDR(t *testing.T) {
 		})
 	}
 }
+
+func TestContainsNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		subnet   string
+		contains bool
+	}{
+		{
+			name:     "IPv4 CIDR that does contain an IPv4 CIDR",
+			network:  "10.0.0.0/16",
+			subnet:   "10.0.4.0/22",
+			contains: true,
+		},
+		{
+			name:     "IPv4 CIDR that does contain itself",
+			network:  "10.0.0.0/16",
+			subnet:   "10.0.0.0/16",
+			contains: true,
+		},
+		{
+			name:     "IPv4 CIDR that does NOT contain a larger IPv4 CIDR",
+			network:  "10.0.0.0/16",
+			subnet:   "10.0.0.0/8",
+			contains: false,
+		},
+		{
+			name:     "IPv6 CIDR that does contain an IPv6 CIDR",
+			network:  "2001:db8::/32",
+			subnet:   "2001:db8:1234::/48",
+			contains: true,
+		},
+		{
+			name:     "IPv6 CIDR that does NOT contain an IPv4 CIDR",
+			network:  "::/0",
+			subnet:   "10.0.0.0/8",
+			contains: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			assert.NoError(t, err)
+			subnet, err := core.ParseCIDR(tt.subnet)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contains, core.ContainsNetwork(network, subnet), "Given subnet should be part of the given CIDR")
+		})
+	}
+}
+
+func TestGetSpecialPurposeRange(t *testing.T) {
+	tests := []struct {
+		name             string
+		cidr             string
+		expectedCategory string
+		expectedFound    bool
+	}{
+		{
+			name:             "Private IPv4 CIDR",
+			cidr:             "10.1.0.0/16",
+			expectedCategory: core.SpecialPurposePrivate,
+			expectedFound:    true,
+		},
+		{
+			name:             "Limited broadcast is more specific than the reserved range",
+			cidr:             "255.255.255.255/32",
+			expectedCategory: core.SpecialPurposeReserved,
+			expectedFound:    true,
+		},
+		{
+			name:             "IPv6 documentation CIDR",
+			cidr:             "2001:db8:1::/48",
+			expectedCategory: core.SpecialPurposeDocumentation,
+			expectedFound:    true,
+		},
+		{
+			name:          "Global unicast IPv4 CIDR",
+			cidr:          "8.8.8.0/24",
+			expectedFound: false,
+		},
+		{
+			name:          "IPv4 CIDR that is larger than a special-purpose range",
+			cidr:          "10.0.0.0/7",
+			expectedFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.cidr)
+			assert.NoError(t, err)
+			special, found := core.GetSpecialPurposeRange(network)
+			assert.Equal(t, tt.expectedFound, found, "Special-purpose range should be found")
+			assert.Equal(t, tt.expectedCategory, special.Category, "Special-purpose category is not correct")
+		})
+	}
+}
+
+func TestGetContainment(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		subnet   string
+		expected core.Containment
+	}{
+		{
+			name:     "IPv4 CIDR that is fully contained",
+			network:  "10.0.0.0/16",
+			subnet:   "10.0.4.0/22",
+			expected: core.FullyContained,
+		},
+		{
+			name:     "IPv4 CIDR that is partially contained",
+			network:  "10.0.0.0/16",
+			subnet:   "10.0.0.0/15",
+			expected: core.PartiallyContained,
+		},
+		{
+			name:     "IPv4 CIDR that is not contained",
+			network:  "10.0.0.0/16",
+			subnet:   "10.1.0.0/16",
+			expected: core.NotContained,
+		},
+		{
+			name:     "IPv6 CIDR that is partially contained",
+			network:  "2001:db8:1234::/48",
+			subnet:   "2001:db8::/32",
+			expected: core.PartiallyContained,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			assert.NoError(t, err)
+			subnet, err := core.ParseCIDR(tt.subnet)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, core.GetContainment(network, subnet), "Containment is not correct")
+		})
+	}
+}
+
+func TestParseAddressFamily(t *testing.T) {
+	ip, err := core.ParseAddress("10.0.14.5")
+	assert.NoError(t, err)
+	assert.Len(t, ip, net.IPv4len, "IPv4 address should be in its 4-byte form")
+
+	ip, err = core.ParseAddress("::ffff:10.0.14.5")
+	assert.NoError(t, err)
+	assert.Len(t, ip, net.IPv6len, "IPv4-mapped address should be in its 16-byte form")
+}
+
+func TestUnmap(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		expected string
+	}{
+		{
+			name:     "IPv4-mapped network",
+			network:  "::ffff:10.0.0.0/104",
+			expected: "10.0.0.0/8",
+		},
+		{
+			name:     "The IPv4-mapped address space",
+			network:  "::ffff:0:0/96",
+			expected: "0.0.0.0/0",
+		},
+		{
+			name:     "IPv6 network that holds the IPv4-mapped address space",
+			network:  "::/64",
+			expected: "::/64",
+		},
+		{
+			name:     "IPv6 network",
+			network:  "2001:db8::/32",
+			expected: "2001:db8::/32",
+		},
+		{
+			name:     "IPv4 network",
+			network:  "10.0.0.0/8",
+			expected: "10.0.0.0/8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			assert.NoError(t, err)
+			expected, err := core.ParseCIDR(tt.expected)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, core.Unmap(network))
+		})
+	}
+
+	assert.Equal(t, net.IP{10, 0, 0, 1}, core.UnmapAddress(net.ParseIP("::ffff:10.0.0.1")))
+	assert.Equal(t, net.ParseIP("2001:db8::1"), core.UnmapAddress(net.ParseIP("2001:db8::1")))
+}
+
+// TestMixedFamilies checks every pairing of IPv4, IPv4-mapped IPv6 and IPv6 networks and addresses,
+// both with the strict default semantics and with IPv4-mapped prefixes unmapped to IPv4.
+func TestMixedFamilies(t *testing.T) {
+	tests := []struct {
+		network  string
+		prefix   string
+		strict   bool
+		unmapped bool
+	}{
+		{network: "10.0.0.0/8", prefix: "10.1.2.3", strict: true, unmapped: true},
+		{network: "10.0.0.0/8", prefix: "::ffff:10.1.2.3", strict: false, unmapped: true},
+		{network: "10.0.0.0/8", prefix: "2001:db8::1", strict: false, unmapped: false},
+		{network: "10.0.0.0/8", prefix: "10.1.0.0/16", strict: true, unmapped: true},
+		{network: "10.0.0.0/8", prefix: "::ffff:10.1.0.0/112", strict: false, unmapped: true},
+		{network: "10.0.0.0/8", prefix: "2001:db8:1::/48", strict: false, unmapped: false},
+
+		{network: "::ffff:10.0.0.0/104", prefix: "10.1.2.3", strict: false, unmapped: true},
+		{network: "::ffff:10.0.0.0/104", prefix: "::ffff:10.1.2.3", strict: true, unmapped: true},
+		{network: "::ffff:10.0.0.0/104", prefix: "2001:db8::1", strict: false, unmapped: false},
+		{network: "::ffff:10.0.0.0/104", prefix: "10.1.0.0/16", strict: false, unmapped: true},
+		{network: "::ffff:10.0.0.0/104", prefix: "::ffff:10.1.0.0/112", strict: true, unmapped: true},
+		{network: "::ffff:10.0.0.0/104", prefix: "2001:db8:1::/48", strict: false, unmapped: false},
+
+		{network: "2001:db8::/32", prefix: "10.1.2.3", strict: false, unmapped: false},
+		{network: "2001:db8::/32", prefix: "::ffff:10.1.2.3", strict: false, unmapped: false},
+		{network: "2001:db8::/32", prefix: "2001:db8::1", strict: true, unmapped: true},
+		{network: "2001:db8::/32", prefix: "10.1.0.0/16", strict: false, unmapped: false},
+		{network: "2001:db8::/32", prefix: "::ffff:10.1.0.0/112", strict: false, unmapped: false},
+		{network: "2001:db8::/32", prefix: "2001:db8:1::/48", strict: true, unmapped: true},
+
+		{network: "::/0", prefix: "10.1.2.3", strict: false, unmapped: false},
+		{network: "::/0", prefix: "::ffff:10.1.2.3", strict: true, unmapped: false},
+		{network: "::/0", prefix: "2001:db8::1", strict: true, unmapped: true},
+		{network: "::/0", prefix: "10.1.0.0/16", strict: false, unmapped: false},
+		{network: "::/0", prefix: "::ffff:10.1.0.0/112", strict: true, unmapped: false},
+		{network: "::/0", prefix: "2001:db8:1::/48", strict: true, unmapped: true},
+	}
+	for _, tt := range tests {
+		for _, unmap := range []bool{false, true} {
+			expected := tt.strict
+			if unmap {
+				expected = tt.unmapped
+			}
+			t.Run(fmt.Sprintf("%s %s unmap=%t", tt.network, tt.prefix, unmap), func(t *testing.T) {
+				network, err := core.ParseCIDR(tt.network)
+				assert.NoError(t, err)
+				if unmap {
+					network = core.Unmap(network)
+				}
+				set := core.NewIPSet(network)
+
+				if !strings.Contains(tt.prefix, "/") {
+					ip, err := core.ParseAddress(tt.prefix)
+					assert.NoError(t, err)
+					if unmap {
+						ip = core.UnmapAddress(ip)
+					}
+					assert.Equal(t, expected, core.ContainsAddress(network, ip), "ContainsAddress is not correct")
+					assert.Equal(t, expected, set.ContainsAddress(ip), "IPSet.ContainsAddress is not correct")
+					return
+				}
+
+				subnet, err := core.ParseCIDR(tt.prefix)
+				assert.NoError(t, err)
+				if unmap {
+					subnet = core.Unmap(subnet)
+				}
+				assert.Equal(t, expected, core.ContainsNetwork(network, subnet), "ContainsNetwork is not correct")
+				assert.False(t, core.ContainsNetwork(subnet, network), "A smaller network should not contain a larger one")
+				assert.Equal(t, expected, core.Overlaps(network, subnet), "Overlaps is not correct")
+				assert.Equal(t, expected, core.Overlaps(subnet, network), "Overlaps should be symmetric")
+				assert.Equal(t, !expected, set.Intersection(core.NewIPSet(subnet)).IsEmpty(), "IPSet.Intersection is not correct")
+			})
+		}
+	}
+}
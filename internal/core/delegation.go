@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// nibbleBits is the number of bits in a nibble, the unit of IPv6 reverse DNS (ip6.arpa) and of a hex digit.
+const nibbleBits = 4
+
+// DelegationLevel is a level of a hierarchical prefix delegation plan, such as the sites in a region.
+type DelegationLevel struct {
+	// Name names the prefixes of the level, such as site.
+	Name string
+	// Bits is the number of bits that the level adds to the prefix length of its parent.
+	Bits int
+	// Count is the number of prefixes that are allocated in every prefix of the parent level.
+	Count int
+}
+
+// Delegation is a prefix in a delegation plan, together with the prefixes that are allocated in it.
+type Delegation struct {
+	// Level is the name of the level that the prefix belongs to, empty for the prefix that is delegated.
+	Level string
+	// Index is the position of the prefix in its parent, which is also its subnet ID.
+	Index int
+	// Network is the prefix.
+	Network *net.IPNet
+	// Children are the prefixes of the next level.
+	Children []*Delegation
+}
+
+// Name returns the name of the prefix in its parent, such as site-2, or its CIDR notation for the delegated prefix.
+func (d *Delegation) Name() string {
+	if d.Level == "" {
+		return FormatNetwork(d.Network)
+	}
+	return fmt.Sprintf("%s-%d", d.Level, d.Index)
+}
+
+// ParseDelegationLevel parses a level in the form name:bits or name:bits:count. Without a count,
+// every prefix that fits in the parent is allocated.
+func ParseDelegationLevel(s string) (DelegationLevel, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return DelegationLevel{}, fmt.Errorf("%w: %s", ErrInvalidDelegationLevel, s)
+	}
+	bits, err := strconv.Atoi(parts[1])
+	if err != nil || bits < 1 {
+		return DelegationLevel{}, fmt.Errorf("%w: %s", ErrInvalidDelegationLevel, s)
+	}
+	level := DelegationLevel{Name: parts[0], Bits: bits}
+	if len(parts) == 3 {
+		level.Count, err = strconv.Atoi(parts[2])
+		if err != nil || level.Count < 1 {
+			return DelegationLevel{}, fmt.Errorf("%w: %s", ErrInvalidDelegationLevel, s)
+		}
+	}
+	return level, nil
+}
+
+// GetDelegationPlan allocates the prefixes of every level in every prefix of the level above it,
+// starting with the delegated network. A level without a count is allocated in full. The prefixes
+// of a level are allocated from the start of their parent, in steps of their own size.
+func GetDelegationPlan(network *net.IPNet, levels []DelegationLevel) (*Delegation, error) {
+	prefixLength, bits := network.Mask.Size()
+	for _, level := range levels {
+		prefixLength += level.Bits
+		if prefixLength > bits {
+			return nil, fmt.Errorf("%w for level %s, /%d is longer than /%d", ErrInsufficientAddressSpace, level.Name, prefixLength, bits)
+		}
+		if level.Count == 0 && level.Bits > 30 {
+			return nil, fmt.Errorf("%w: level %s would allocate 2^%d prefixes, give it a count", ErrInvalidDelegationLevel, level.Name, level.Bits)
+		}
+		if level.Count > 0 && big.NewInt(int64(level.Count)).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(level.Bits))) > 0 {
+			return nil, fmt.Errorf("%w for %d prefixes of level %s in %d bits", ErrInsufficientAddressSpace, level.Count, level.Name, level.Bits)
+		}
+	}
+
+	root := &Delegation{Network: &net.IPNet{IP: networkIP(network).Mask(network.Mask), Mask: network.Mask}}
+	allocate(root, levels)
+	return root, nil
+}
+
+// allocate adds the prefixes of the first level as children of parent, and recursively those of the next levels.
+func allocate(parent *Delegation, levels []DelegationLevel) {
+	if len(levels) == 0 {
+		return
+	}
+	level := levels[0]
+	ones, bits := parent.Network.Mask.Size()
+	mask := net.CIDRMask(ones+level.Bits, bits)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones-level.Bits))
+	count := level.Count
+	if count == 0 {
+		count = 1 << level.Bits
+	}
+
+	base := new(big.Int).SetBytes(parent.Network.IP)
+	for i := range count {
+		offset := new(big.Int).Mul(size, big.NewInt(int64(i)))
+		child := &Delegation{
+			Level:   level.Name,
+			Index:   i,
+			Network: &net.IPNet{IP: bigIntToIP(offset.Add(offset, base), len(parent.Network.IP)), Mask: mask},
+		}
+		allocate(child, levels[1:])
+		parent.Children = append(parent.Children, child)
+	}
+}
+
+// GetDelegationCount returns the number of prefixes that a plan allocates, the delegated network excluded.
+func GetDelegationCount(levels []DelegationLevel) *big.Int {
+	total := new(big.Int)
+	prefixes := big.NewInt(1)
+	for _, level := range levels {
+		count := big.NewInt(int64(level.Count))
+		if level.Count == 0 {
+			count = new(big.Int).Lsh(big.NewInt(1), uint(level.Bits))
+		}
+		prefixes.Mul(prefixes, count)
+		total.Add(total, prefixes)
+	}
+	return total
+}
+
+// GetDelegationWarnings reports the levels of an IPv6 plan whose prefixes are not on a nibble boundary,
+// which makes their reverse DNS zones and hex digits harder to delegate and read, and a last level of
+// LAN subnets that are not /64, the prefix length that SLAAC needs. IPv4 plans have no warnings.
+func GetDelegationWarnings(network *net.IPNet, levels []DelegationLevel) []string {
+	prefixLength, bits := network.Mask.Size()
+	if bits != 8*net.IPv6len {
+		return nil
+	}
+
+	var warnings []string
+	if prefixLength%nibbleBits != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s is not on a nibble boundary", FormatNetwork(network)))
+	}
+	for _, level := range levels {
+		prefixLength += level.Bits
+		if prefixLength%nibbleBits != 0 {
+			warnings = append(warnings, fmt.Sprintf("level %s is /%d, which is not on a nibble boundary", level.Name, prefixLength))
+		}
+	}
+	if len(levels) > 0 && prefixLength != InterfaceIdentifierPrefixLength {
+		warnings = append(warnings, fmt.Sprintf("LAN subnets of level %s are /%d, SLAAC needs /%d", levels[len(levels)-1].Name, prefixLength, InterfaceIdentifierPrefixLength))
+	}
+	return warnings
+}
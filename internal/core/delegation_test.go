@@ -0,0 +1,195 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDelegationLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected core.DelegationLevel
+		wantErr  bool
+	}{
+		{
+			name:     "Parse a level with a count",
+			input:    "site:12:4",
+			expected: core.DelegationLevel{Name: "site", Bits: 12, Count: 4},
+		},
+		{
+			name:     "Parse a level without a count",
+			input:    "region:4",
+			expected: core.DelegationLevel{Name: "region", Bits: 4},
+		},
+		{
+			name:    "Reject a level without bits",
+			input:   "site",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a level without a name",
+			input:   ":4:2",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a level of 0 bits",
+			input:   "site:0",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a count of 0",
+			input:   "site:4:0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := core.ParseDelegationLevel(tt.input)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, core.ErrInvalidDelegationLevel), "expected %v, got %v", core.ErrInvalidDelegationLevel, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+// flattenDelegations returns the names and prefixes of a plan in the order they are allocated.
+func flattenDelegations(delegations []*core.Delegation) []string {
+	var result []string
+	for _, delegation := range delegations {
+		result = append(result, delegation.Name()+" "+core.FormatNetwork(delegation.Network))
+		result = append(result, flattenDelegations(delegation.Children)...)
+	}
+	return result
+}
+
+func TestGetDelegationPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		levels   []core.DelegationLevel
+		expected []string
+		wantErr  error
+	}{
+		{
+			name:    "Plan regions, sites and VLANs",
+			network: "2001:db8::/32",
+			levels: []core.DelegationLevel{
+				{Name: "region", Bits: 4, Count: 2},
+				{Name: "site", Bits: 12, Count: 2},
+				{Name: "vlan", Bits: 16, Count: 1},
+			},
+			expected: []string{
+				"region-0 2001:db8::/36",
+				"site-0 2001:db8::/48",
+				"vlan-0 2001:db8::/64",
+				"site-1 2001:db8:1::/48",
+				"vlan-0 2001:db8:1::/64",
+				"region-1 2001:db8:1000::/36",
+				"site-0 2001:db8:1000::/48",
+				"vlan-0 2001:db8:1000::/64",
+				"site-1 2001:db8:1001::/48",
+				"vlan-0 2001:db8:1001::/64",
+			},
+		},
+		{
+			name:     "Allocate a level without count in full",
+			network:  "10.0.0.0/22",
+			levels:   []core.DelegationLevel{{Name: "lan", Bits: 2}},
+			expected: []string{"lan-0 10.0.0.0/24", "lan-1 10.0.1.0/24", "lan-2 10.0.2.0/24", "lan-3 10.0.3.0/24"},
+		},
+		{
+			name:     "Allocate from the base of a network with a host address",
+			network:  "10.0.3.1/22",
+			levels:   []core.DelegationLevel{{Name: "lan", Bits: 1, Count: 1}},
+			expected: []string{"lan-0 10.0.0.0/23"},
+		},
+		{
+			name:    "Reject levels that are longer than the address space",
+			network: "2001:db8::/32",
+			levels:  []core.DelegationLevel{{Name: "site", Bits: 16, Count: 1}, {Name: "host", Bits: 81, Count: 1}},
+			wantErr: core.ErrInsufficientAddressSpace,
+		},
+		{
+			name:    "Reject a count that does not fit in the bits of the level",
+			network: "2001:db8::/32",
+			levels:  []core.DelegationLevel{{Name: "region", Bits: 4, Count: 17}},
+			wantErr: core.ErrInsufficientAddressSpace,
+		},
+		{
+			name:    "Reject a level without count that is too large to allocate in full",
+			network: "2001:db8::/32",
+			levels:  []core.DelegationLevel{{Name: "vlan", Bits: 32}},
+			wantErr: core.ErrInvalidDelegationLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			require.NoError(t, err)
+
+			plan, err := core.GetDelegationPlan(network, tt.levels)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, flattenDelegations(plan.Children))
+			assert.Equal(t, int64(len(tt.expected)), core.GetDelegationCount(tt.levels).Int64())
+		})
+	}
+}
+
+func TestGetDelegationWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		levels   []core.DelegationLevel
+		expected []string
+	}{
+		{
+			name:    "Nibble aligned plan with /64 LAN subnets",
+			network: "2001:db8::/32",
+			levels:  []core.DelegationLevel{{Name: "site", Bits: 16}, {Name: "vlan", Bits: 16}},
+		},
+		{
+			name:    "Levels that are not on a nibble boundary",
+			network: "2001:db8::/32",
+			levels:  []core.DelegationLevel{{Name: "region", Bits: 3}, {Name: "site", Bits: 13}, {Name: "vlan", Bits: 16}},
+			expected: []string{
+				"level region is /35, which is not on a nibble boundary",
+			},
+		},
+		{
+			name:    "LAN subnets that are not /64",
+			network: "2001:db8::/47",
+			levels:  []core.DelegationLevel{{Name: "vlan", Bits: 9}},
+			expected: []string{
+				"2001:db8::/47 is not on a nibble boundary",
+				"LAN subnets of level vlan are /56, SLAAC needs /64",
+			},
+		},
+		{
+			name:    "IPv4 plans have no warnings",
+			network: "10.0.0.0/8",
+			levels:  []core.DelegationLevel{{Name: "site", Bits: 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, core.GetDelegationWarnings(network, tt.levels))
+		})
+	}
+}
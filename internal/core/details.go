@@ -0,0 +1,85 @@
+package core
+
+import (
+	"net"
+
+	"github.com/bschaatsbergen/cidr/internal/helper"
+)
+
+// NetworkDetails are the details of a network as explain displays them, the addresses and counts are
+// formatted for humans.
+type NetworkDetails struct {
+	IsIPV4Network              bool
+	IsIPV6Network              bool
+	BroadcastAddress           string
+	BroadcastAddressHasError   bool
+	Netmask                    net.IP
+	PrefixLength               int
+	BaseAddress                net.IP
+	Count                      string
+	HostCount                  string
+	UsableAddressRangeHasError bool
+	FirstUsableIPAddress       string
+	LastUsableIPAddress        string
+}
+
+// GetNetworkDetails returns the details of the network. The broadcast address holds the error message if
+// the network has none, and the usable range is left empty if it cannot be calculated.
+func GetNetworkDetails(network *net.IPNet) *NetworkDetails {
+	details := &NetworkDetails{}
+
+	// Determine whether the network is an IPv4 or IPv6 network.
+	if helper.IsIPv4Network(network) {
+		details.IsIPV4Network = true
+	} else if helper.IsIPv6Network(network) {
+		details.IsIPV6Network = true
+	}
+
+	// Obtain the broadcast address, handling errors if they occur.
+	ipBroadcast, err := GetBroadcastAddress(network)
+	if err != nil {
+		// Set error flags and store the error message so that it can be displayed later.
+		details.BroadcastAddressHasError = true
+		details.BroadcastAddress = err.Error()
+	} else {
+		details.BroadcastAddress = FormatAddress(ipBroadcast)
+	}
+
+	// Obtain the netmask and prefix length.
+	netmask := GetNetmask(network)
+	// A human-readable representation of the netmask is displayed in the output.
+	details.Netmask = NetMaskToIPAddress(netmask)
+	details.PrefixLength = GetPrefixLength(details.Netmask)
+
+	// Obtain the base address of the network.
+	details.BaseAddress = GetBaseAddress(network)
+
+	// Obtain the total count of addresses in the network.
+	count := GetAddressCount(network)
+	// Format the count as a human-readable string and store it in the details struct.
+	details.Count = helper.FormatNumber(count.String())
+
+	// Obtain the total count of distinct host addresses in the network.
+	hostCount := GetHostAddressCount(network)
+	// Format the count as a human-readable string and store it in the details struct.
+	details.HostCount = helper.FormatNumber(hostCount.String())
+
+	// Obtain the first and last usable IP addresses, handling errors if they occur.
+	firstUsableIP, err := GetFirstUsableIPAddress(network)
+	if err != nil {
+		// Set error flags if an error occurs during the retrieval of the first usable IP address.
+		details.UsableAddressRangeHasError = true
+	} else {
+		details.FirstUsableIPAddress = FormatAddress(firstUsableIP)
+	}
+
+	lastUsableIP, err := GetLastUsableIPAddress(network)
+	if err != nil {
+		// Set error flags if an error occurs during the retrieval of the last usable IP address.
+		details.UsableAddressRangeHasError = true
+	} else {
+		details.LastUsableIPAddress = FormatAddress(lastUsableIP)
+	}
+
+	return details
+}
@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNetworkDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidr     string
+		expected *core.NetworkDetails
+	}{
+		{
+			name: "IPv4 network",
+			cidr: "10.1.0.0/16",
+			expected: &core.NetworkDetails{
+				IsIPV4Network:        true,
+				BroadcastAddress:     "10.1.255.255",
+				Netmask:              net.ParseIP("255.255.0.0").To4(),
+				PrefixLength:         16,
+				BaseAddress:          net.ParseIP("10.1.0.0").To4(),
+				Count:                "65,536",
+				HostCount:            "65,534",
+				FirstUsableIPAddress: "10.1.0.1",
+				LastUsableIPAddress:  "10.1.255.254",
+			},
+		},
+		{
+			name: "IPv4 network without a broadcast address or usable range",
+			cidr: "10.1.0.1/32",
+			expected: &core.NetworkDetails{
+				IsIPV4Network:              true,
+				BroadcastAddress:           "IPv4 network has no broadcast address",
+				BroadcastAddressHasError:   true,
+				Netmask:                    net.ParseIP("255.255.255.255").To4(),
+				PrefixLength:               32,
+				BaseAddress:                net.ParseIP("10.1.0.1").To4(),
+				Count:                      "1",
+				HostCount:                  "1",
+				UsableAddressRangeHasError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.cidr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, core.GetNetworkDetails(network))
+		})
+	}
+}
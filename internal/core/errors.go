@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidCIDR is returned when a string cannot be parsed as a CIDR range.
+	ErrInvalidCIDR = errors.New("invalid CIDR range")
+	// ErrInvalidAddress is returned when a string cannot be parsed as an IP address.
+	ErrInvalidAddress = errors.New("invalid IP address")
+
+	// ErrNoBroadcastAddress is wrapped by every error that reports a network without a broadcast address.
+	ErrNoBroadcastAddress = errors.New("network has no broadcast address")
+	// ErrNoUsableAddress is wrapped by every error that reports a network without a usable address range.
+	ErrNoUsableAddress = errors.New("network has no usable address")
+
+	ErrIPv6HasNoBroadcastAddress error = &detailedError{msg: "IPv6 network has no broadcast addresses", err: ErrNoBroadcastAddress}
+	ErrIPv4HasNoBroadcastAddress error = &detailedError{msg: "IPv4 network has no broadcast address", err: ErrNoBroadcastAddress}
+
+	ErrIPv4NetworkHasNoFirstUsableAddress error = &detailedError{msg: "IPv4 network has no first usable address", err: ErrNoUsableAddress}
+	ErrIPv6NetworkHasNoFirstUsableAddress error = &detailedError{msg: "IPv6 network has no first usable address", err: ErrNoUsableAddress}
+
+	ErrIPv4NetworkHasNoLastUsableAddress error = &detailedError{msg: "IPv4 network has no last usable address", err: ErrNoUsableAddress}
+	ErrIPv6NetworkHasNoLastUsableAddress error = &detailedError{msg: "IPv6 network has no last usable address", err: ErrNoUsableAddress}
+
+	// ErrInvalidDivisor is returned when a network cannot be divided by the given divisor.
+	ErrInvalidDivisor = errors.New("invalid divisor")
+	// ErrInsufficientAddressSpace is returned when a network is too small for the requested operation.
+	ErrInsufficientAddressSpace = errors.New("address space is insufficient")
+	// ErrOutsideNetwork is returned when a subnet is expected to be part of a network, but is not.
+	ErrOutsideNetwork = errors.New("outside of the network")
+
+	// ErrAddressOverflow is returned when address arithmetic results in a value outside of the address family.
+	ErrAddressOverflow = errors.New("address overflow")
+	// ErrInvalidEUI64 is returned when a string cannot be parsed as a MAC address or EUI-64 identifier.
+	ErrInvalidEUI64 = errors.New("invalid MAC address or EUI-64")
+	// ErrInvalidInterfacePrefix is returned when a prefix is not an IPv6 /64 that hosts can assign addresses in.
+	ErrInvalidInterfacePrefix = errors.New("interface identifiers need an IPv6 /64 prefix")
+	// ErrNotEUI64Address is returned when the interface identifier of an address is not in the modified EUI-64 format.
+	ErrNotEUI64Address = errors.New("address has no EUI-64 interface identifier")
+	// ErrInvalidDelegationLevel is returned when a string cannot be parsed as a level of a delegation plan.
+	ErrInvalidDelegationLevel = errors.New("invalid level, expected name:bits or name:bits:count")
+	// ErrAddressFamilyMismatch is returned when an operation needs addresses of the same address family.
+	ErrAddressFamilyMismatch = errors.New("addresses are of different address families")
+)
+
+// ParseError records the input that could not be parsed and the reason why.
+// Err is one of ErrInvalidCIDR or ErrInvalidAddress, so callers can match it with errors.Is.
+type ParseError struct {
+	Input string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err, e.Input)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// detailedError is a more specific variant of a sentinel error, it matches the sentinel with errors.Is
+// while keeping its own message.
+type detailedError struct {
+	msg string
+	err error
+}
+
+func (e *detailedError) Error() string {
+	return e.msg
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// InterfaceIdentifierPrefixLength is the prefix length of the networks that hosts assign addresses in with SLAAC (RFC 4862).
+const InterfaceIdentifierPrefixLength = 64
+
+// LinkLocalNetwork is fe80::/64, the network of the link-local addresses that hosts assign to their interfaces.
+var LinkLocalNetwork = mustParseCIDR("fe80::/64")
+
+// reservedInterfaceIdentifiers are the ranges of interface identifiers that are reserved by RFC 5453,
+// and are never assigned as stable privacy address.
+var reservedInterfaceIdentifiers = [][2]uint64{
+	{0x0000000000000000, 0x0000000000000000}, // Subnet-Router anycast (RFC 4291)
+	{0x02005efffe000000, 0x02005efffeffffff}, // Interface identifiers of the IANA Ethernet block, including Proxy Mobile IPv6 (RFC 6543)
+	{0xfdffffffffffff80, 0xfdffffffffffffff}, // Reserved subnet anycast addresses (RFC 2526)
+}
+
+// GetInterfaceIdentifierAddress returns the address in an IPv6 /64 prefix with the given 64-bit interface identifier.
+func GetInterfaceIdentifierAddress(prefix *net.IPNet, iid []byte) (net.IP, error) {
+	ones, bits := prefix.Mask.Size()
+	if bits != 8*net.IPv6len || ones != InterfaceIdentifierPrefixLength {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInterfacePrefix, FormatNetwork(prefix))
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, prefix.IP.Mask(prefix.Mask))
+	copy(ip[8:], iid)
+	return ip, nil
+}
+
+// GetEUI64Address returns the address that a host with the given modified EUI-64 identifier, as returned
+// by ParseEUI64, assigns itself in an IPv6 /64 prefix.
+func GetEUI64Address(prefix *net.IPNet, eui64 []byte) (net.IP, error) {
+	return GetInterfaceIdentifierAddress(prefix, eui64)
+}
+
+// GetStablePrivacyAddress returns the semantically opaque address that a host assigns itself in an IPv6 /64
+// prefix with the algorithm of RFC 7217. The pseudorandom function is SHA-256 over the prefix, the interface,
+// the network ID, the DAD counter as a single byte and the secret key, of which the first 64 bits are used.
+// When this results in a reserved interface identifier, the DAD counter is incremented as the RFC prescribes.
+func GetStablePrivacyAddress(prefix *net.IPNet, netIface, networkID []byte, dadCounter uint8, secretKey []byte) (net.IP, error) {
+	for {
+		var buf bytes.Buffer
+		buf.Write(prefix.IP.Mask(prefix.Mask).To16()[:8])
+		buf.Write(netIface)
+		buf.Write(networkID)
+		buf.WriteByte(dadCounter)
+		buf.Write(secretKey)
+		digest := sha256.Sum256(buf.Bytes())
+
+		if !isReservedInterfaceIdentifier(binary.BigEndian.Uint64(digest[:8])) || dadCounter == 255 {
+			return GetInterfaceIdentifierAddress(prefix, digest[:8])
+		}
+		dadCounter++
+	}
+}
+
+// GetMACFromEUI64Address returns the 48-bit MAC address that a modified EUI-64 interface identifier was made of.
+func GetMACFromEUI64Address(ip net.IP) (net.HardwareAddr, error) {
+	if len(ip) != net.IPv6len || ip[11] != 0xff || ip[12] != 0xfe {
+		return nil, fmt.Errorf("%w: %s", ErrNotEUI64Address, FormatAddress(ip))
+	}
+	return net.HardwareAddr{ip[8] ^ 0x02, ip[9], ip[10], ip[13], ip[14], ip[15]}, nil
+}
+
+func isReservedInterfaceIdentifier(iid uint64) bool {
+	for _, reserved := range reservedInterfaceIdentifiers {
+		if iid >= reserved[0] && iid <= reserved[1] {
+			return true
+		}
+	}
+	return false
+}
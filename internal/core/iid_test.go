@@ -0,0 +1,165 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEUI64Address(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		mac      string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "Assign an address from a MAC address",
+			prefix:   "2001:db8:1:2::/64",
+			mac:      "00:1a:2b:3c:4d:5e",
+			expected: "2001:db8:1:2:21a:2bff:fe3c:4d5e",
+		},
+		{
+			name:     "Assign a link-local address",
+			prefix:   "fe80::/64",
+			mac:      "00:1a:2b:3c:4d:5e",
+			expected: "fe80::21a:2bff:fe3c:4d5e",
+		},
+		{
+			name:     "Assign an address in the prefix that a host address belongs to",
+			prefix:   "2001:db8:1:2::1/64",
+			mac:      "00:1a:2b:3c:4d:5e",
+			expected: "2001:db8:1:2:21a:2bff:fe3c:4d5e",
+		},
+		{
+			name:     "Assign the same address from the EUI-64 of the MAC address",
+			prefix:   "2001:db8:1:2::/64",
+			mac:      "00:1a:2b:ff:fe:3c:4d:5e",
+			expected: "2001:db8:1:2:21a:2bff:fe3c:4d5e",
+		},
+		{
+			name:     "Invert the universal/local bit of a locally administered EUI-64",
+			prefix:   "2001:db8:1:2::/64",
+			mac:      "02:1a:2b:ff:fe:3c:4d:5e",
+			expected: "2001:db8:1:2:1a:2bff:fe3c:4d5e",
+		},
+		{
+			name:    "Reject a prefix shorter than /64",
+			prefix:  "2001:db8::/48",
+			mac:     "00:1a:2b:3c:4d:5e",
+			wantErr: core.ErrInvalidInterfacePrefix,
+		},
+		{
+			name:    "Reject an IPv4 network",
+			prefix:  "10.0.0.0/8",
+			mac:     "00:1a:2b:3c:4d:5e",
+			wantErr: core.ErrInvalidInterfacePrefix,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, err := core.ParseCIDR(tt.prefix)
+			require.NoError(t, err)
+			eui64, err := core.ParseEUI64(tt.mac)
+			require.NoError(t, err)
+
+			ip, err := core.GetEUI64Address(prefix, eui64)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, core.FormatAddress(ip))
+		})
+	}
+}
+
+func TestGetStablePrivacyAddress(t *testing.T) {
+	prefix, err := core.ParseCIDR("2001:db8:1:2::/64")
+	require.NoError(t, err)
+
+	address, err := core.GetStablePrivacyAddress(prefix, []byte("eth0"), nil, 0, []byte("s3cr3t"))
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8:1:2:eb1b:a078:4149:7e1c", core.FormatAddress(address))
+
+	again, err := core.GetStablePrivacyAddress(prefix, []byte("eth0"), nil, 0, []byte("s3cr3t"))
+	require.NoError(t, err)
+	assert.Equal(t, address, again, "the address must be stable")
+
+	variations := []struct {
+		name       string
+		prefix     string
+		netIface   string
+		networkID  string
+		dadCounter uint8
+		secretKey  string
+	}{
+		{name: "Another prefix", prefix: "2001:db8:1:3::/64", netIface: "eth0", secretKey: "s3cr3t"},
+		{name: "Another interface", prefix: "2001:db8:1:2::/64", netIface: "eth1", secretKey: "s3cr3t"},
+		{name: "Another network ID", prefix: "2001:db8:1:2::/64", netIface: "eth0", networkID: "guest", secretKey: "s3cr3t"},
+		{name: "Another DAD counter", prefix: "2001:db8:1:2::/64", netIface: "eth0", dadCounter: 1, secretKey: "s3cr3t"},
+		{name: "Another secret key", prefix: "2001:db8:1:2::/64", netIface: "eth0", secretKey: "other"},
+	}
+	for _, tt := range variations {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := core.ParseCIDR(tt.prefix)
+			require.NoError(t, err)
+			ip, err := core.GetStablePrivacyAddress(p, []byte(tt.netIface), []byte(tt.networkID), tt.dadCounter, []byte(tt.secretKey))
+			require.NoError(t, err)
+			assert.NotEqual(t, address, ip)
+			assert.True(t, core.ContainsAddress(p, ip))
+		})
+	}
+
+	shortPrefix, err := core.ParseCIDR("2001:db8::/48")
+	require.NoError(t, err)
+	_, err = core.GetStablePrivacyAddress(shortPrefix, []byte("eth0"), nil, 0, []byte("s3cr3t"))
+	assert.True(t, errors.Is(err, core.ErrInvalidInterfacePrefix), "expected %v, got %v", core.ErrInvalidInterfacePrefix, err)
+}
+
+func TestGetMACFromEUI64Address(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "Extract the MAC address from an EUI-64 address",
+			address:  "2001:db8:1:2:21a:2bff:fe3c:4d5e",
+			expected: "00:1a:2b:3c:4d:5e",
+		},
+		{
+			name:     "Extract the MAC address from a link-local address",
+			address:  "fe80::21a:2bff:fe3c:4d5e",
+			expected: "00:1a:2b:3c:4d:5e",
+		},
+		{
+			name:    "Reject an address without EUI-64 interface identifier",
+			address: "2001:db8::1",
+			wantErr: core.ErrNotEUI64Address,
+		},
+		{
+			name:    "Reject an IPv4 address",
+			address: "10.0.0.1",
+			wantErr: core.ErrNotEUI64Address,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mac, err := core.GetMACFromEUI64Address(mustParseAddress(t, tt.address))
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mac.String())
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"net"
+	"sort"
+)
+
+// IPRange is an inclusive range of addresses that belong to the same address family.
+// IPv4 addresses are stored in their 4-byte form, IPv6 addresses in their 16-byte form.
+type IPRange struct {
+	First net.IP
+	Last  net.IP
+}
+
+// IPSet is a set of IP addresses of both address families. It is stored as a sorted list of
+// ranges that neither overlap nor touch, IPv4 ranges before IPv6 ranges.
+// The zero value is an empty set. An IPSet is never modified once it is created.
+type IPSet struct {
+	ranges []IPRange
+}
+
+// NewIPSet returns the set of all addresses in the given networks.
+func NewIPSet(networks ...*net.IPNet) *IPSet {
+	ranges := make([]IPRange, 0, len(networks))
+	for _, network := range networks {
+		ranges = append(ranges, GetNetworkRange(network))
+	}
+	return newIPSetFromRanges(ranges)
+}
+
+// GetNetworkRange returns the range of addresses that the given network spans.
+func GetNetworkRange(network *net.IPNet) IPRange {
+	first := networkIP(network)
+	last := make(net.IP, len(first))
+	for i := range first {
+		first[i] &= network.Mask[i]
+		last[i] = first[i] | ^network.Mask[i]
+	}
+	return IPRange{First: first, Last: last}
+}
+
+// networkIP returns a copy of the network's IP address, in the form that matches its mask.
+func networkIP(network *net.IPNet) net.IP {
+	ip := network.IP.To16()
+	if len(network.Mask) == net.IPv4len {
+		ip = network.IP.To4()
+	}
+	return append(net.IP(nil), ip...)
+}
+
+// newIPSetFromRanges sorts and merges the given ranges into a set.
+func newIPSetFromRanges(ranges []IPRange) *IPSet {
+	sort.Slice(ranges, func(i, j int) bool {
+		return compareIP(ranges[i].First, ranges[j].First) < 0
+	})
+
+	merged := make([]IPRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && len(merged[n-1].Last) == len(r.First) {
+			last := &merged[n-1]
+			if next, ok := nextIP(last.Last); !ok || compareIP(r.First, next) <= 0 {
+				if compareIP(r.Last, last.Last) > 0 {
+					last.Last = r.Last
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return &IPSet{ranges: merged}
+}
+
+// Ranges returns the sorted ranges that make up the set.
+func (s *IPSet) Ranges() []IPRange {
+	ranges := make([]IPRange, len(s.ranges))
+	copy(ranges, s.ranges)
+	return ranges
+}
+
+// Networks returns the smallest sorted list of CIDR ranges that covers exactly the addresses in the set.
+func (s *IPSet) Networks() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, r := range s.ranges {
+		networks = append(networks, r.Networks()...)
+	}
+	return networks
+}
+
+// IsEmpty reports whether the set holds no addresses at all.
+func (s *IPSet) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+// Equal reports whether both sets hold exactly the same addresses.
+func (s *IPSet) Equal(other *IPSet) bool {
+	if len(s.ranges) != len(other.ranges) {
+		return false
+	}
+	for i := range s.ranges {
+		if !s.ranges[i].First.Equal(other.ranges[i].First) || !s.ranges[i].Last.Equal(other.ranges[i].Last) {
+			return false
+		}
+	}
+	return true
+}
+
+// IPv4 returns the subset of IPv4 addresses.
+func (s *IPSet) IPv4() *IPSet {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return len(s.ranges[i].First) != net.IPv4len
+	})
+	return &IPSet{ranges: s.ranges[:i]}
+}
+
+// IPv6 returns the subset of IPv6 addresses.
+func (s *IPSet) IPv6() *IPSet {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return len(s.ranges[i].First) != net.IPv4len
+	})
+	return &IPSet{ranges: s.ranges[i:]}
+}
+
+// GetAddressCount returns the total number of addresses in the set.
+func (s *IPSet) GetAddressCount() *big.Int {
+	count := big.NewInt(0)
+	for _, r := range s.ranges {
+		count.Add(count, r.GetAddressCount())
+	}
+	return count
+}
+
+// Union returns the set of addresses that are in either set.
+func (s *IPSet) Union(other *IPSet) *IPSet {
+	ranges := make([]IPRange, 0, len(s.ranges)+len(other.ranges))
+	ranges = append(ranges, s.ranges...)
+	ranges = append(ranges, other.ranges...)
+	return newIPSetFromRanges(ranges)
+}
+
+// Difference returns the set of addresses that are in s, but not in other.
+func (s *IPSet) Difference(other *IPSet) *IPSet {
+	var ranges []IPRange
+	j := 0
+	for _, r := range s.ranges {
+		first := r.First
+		// Skip the ranges of the other set that end before this range starts.
+		for j < len(other.ranges) && compareIP(other.ranges[j].Last, first) < 0 {
+			j++
+		}
+		exhausted := false
+		for k := j; k < len(other.ranges) && compareIP(other.ranges[k].First, r.Last) <= 0; k++ {
+			cut := other.ranges[k]
+			if compareIP(cut.First, first) > 0 {
+				previous, _ := previousIP(cut.First)
+				ranges = append(ranges, IPRange{First: first, Last: previous})
+			}
+			next, ok := nextIP(cut.Last)
+			if !ok || compareIP(cut.Last, r.Last) >= 0 {
+				exhausted = true
+				break
+			}
+			first = next
+		}
+		if !exhausted {
+			ranges = append(ranges, IPRange{First: first, Last: r.Last})
+		}
+	}
+	return &IPSet{ranges: ranges}
+}
+
+// Intersection returns the set of addresses that are in both sets.
+func (s *IPSet) Intersection(other *IPSet) *IPSet {
+	var ranges []IPRange
+	i, j := 0, 0
+	for i < len(s.ranges) && j < len(other.ranges) {
+		a, b := s.ranges[i], other.ranges[j]
+		first, last := maxIP(a.First, b.First), minIP(a.Last, b.Last)
+		if compareIP(first, last) <= 0 {
+			ranges = append(ranges, IPRange{First: first, Last: last})
+		}
+		if compareIP(a.Last, b.Last) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &IPSet{ranges: ranges}
+}
+
+// SymmetricDifference returns the set of addresses that are in exactly one of both sets.
+func (s *IPSet) SymmetricDifference(other *IPSet) *IPSet {
+	return s.Union(other).Difference(s.Intersection(other))
+}
+
+// ContainsAddress checks if the set contains the specified IP address.
+// Like ContainsAddress, a 4-byte address is IPv4 and a 16-byte address is IPv6, even if it is IPv4-mapped.
+func (s *IPSet) ContainsAddress(ip net.IP) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return compareIP(s.ranges[i].Last, ip) >= 0
+	})
+	return i < len(s.ranges) && compareIP(s.ranges[i].First, ip) <= 0
+}
+
+// ContainsNetwork checks if every address of the specified network is in the set, also when the
+// network is only covered by several networks of the set together.
+func (s *IPSet) ContainsNetwork(network *net.IPNet) bool {
+	r := GetNetworkRange(network)
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return compareIP(s.ranges[i].Last, r.First) >= 0
+	})
+	return i < len(s.ranges) && compareIP(s.ranges[i].First, r.First) <= 0 && compareIP(r.Last, s.ranges[i].Last) <= 0
+}
+
+// GetAddressCount returns the number of addresses in the range.
+func (r IPRange) GetAddressCount() *big.Int {
+	count := new(big.Int).Sub(new(big.Int).SetBytes(r.Last), new(big.Int).SetBytes(r.First))
+	return count.Add(count, big.NewInt(1))
+}
+
+// Networks returns the smallest sorted list of CIDR ranges that covers exactly the addresses in the range.
+func (r IPRange) Networks() []*net.IPNet {
+	bits := len(r.First) * 8
+	first := new(big.Int).SetBytes(r.First)
+	last := new(big.Int).SetBytes(r.Last)
+
+	var networks []*net.IPNet
+	for first.Cmp(last) <= 0 {
+		// The block is limited by the alignment of its first address and by the addresses that are left.
+		remaining := new(big.Int).Sub(last, first)
+		remaining.Add(remaining, big.NewInt(1))
+		hostBits := remaining.BitLen() - 1
+		if first.Sign() != 0 && int(first.TrailingZeroBits()) < hostBits {
+			hostBits = int(first.TrailingZeroBits())
+		}
+		networks = append(networks, &net.IPNet{
+			IP:   bigIntToIP(first, len(r.First)),
+			Mask: net.CIDRMask(bits-hostBits, bits),
+		})
+		first.Add(first, new(big.Int).Lsh(big.NewInt(1), uint(hostBits)))
+	}
+	return networks
+}
+
+// bigIntToIP converts an integer into an IP address of the given length in bytes.
+func bigIntToIP(i *big.Int, length int) net.IP {
+	return i.FillBytes(make(net.IP, length))
+}
+
+// CompareNetworks orders networks by address family (IPv4 first), base address and prefix length.
+// It returns a negative number when a sorts before b, a positive number when b sorts before a and zero when both are equal.
+func CompareNetworks(a, b *net.IPNet) int {
+	if c := compareIP(networkIP(a), networkIP(b)); c != 0 {
+		return c
+	}
+	aOnes, _ := a.Mask.Size()
+	bOnes, _ := b.Mask.Size()
+	return aOnes - bOnes
+}
+
+// compareIP orders IP addresses by length, IPv4 before IPv6, and then by value.
+func compareIP(a, b net.IP) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return bytes.Compare(a, b)
+}
+
+func minIP(a, b net.IP) net.IP {
+	if compareIP(a, b) < 0 {
+		return a
+	}
+	return b
+}
+
+func maxIP(a, b net.IP) net.IP {
+	if compareIP(a, b) > 0 {
+		return a
+	}
+	return b
+}
+
+// nextIP returns the address that follows ip, or false if ip is the last address of its family.
+func nextIP(ip net.IP) (net.IP, bool) {
+	next := append(net.IP(nil), ip...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next, true
+		}
+	}
+	return nil, false
+}
+
+// previousIP returns the address that precedes ip, or false if ip is the first address of its family.
+func previousIP(ip net.IP) (net.IP, bool) {
+	previous := append(net.IP(nil), ip...)
+	for i := len(previous) - 1; i >= 0; i-- {
+		previous[i]--
+		if previous[i] != 0xff {
+			return previous, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,168 @@
+package core_test
+
+import (
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIPSet(t *testing.T, cidrs ...string) *core.IPSet {
+	t.Helper()
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		network, err := core.ParseCIDR(cidr)
+		assert.NoError(t, err)
+		networks = append(networks, network)
+	}
+	return core.NewIPSet(networks...)
+}
+
+func networkStrings(networks []*net.IPNet) []string {
+	strs := make([]string, 0, len(networks))
+	for _, network := range networks {
+		strs = append(strs, network.String())
+	}
+	return strs
+}
+
+func TestNewIPSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidrs    []string
+		expected []string
+	}{
+		{
+			name:     "Aggregate adjacent IPv4 CIDRs",
+			cidrs:    []string{"10.0.1.0/24", "10.0.0.0/24"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "Aggregate nested and duplicate IPv4 CIDRs",
+			cidrs:    []string{"10.0.0.0/16", "10.0.4.0/22", "10.0.0.0/16"},
+			expected: []string{"10.0.0.0/16"},
+		},
+		{
+			name:     "Keep CIDRs that cannot be merged into one",
+			cidrs:    []string{"10.0.1.0/24", "10.0.2.0/24"},
+			expected: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		{
+			name:     "Keep address families apart, IPv4 first",
+			cidrs:    []string{"2001:db8::/33", "10.0.0.0/8", "2001:db8:8000::/33"},
+			expected: []string{"10.0.0.0/8", "2001:db8::/32"},
+		},
+		{
+			name:     "Aggregate the whole IPv6 address space",
+			cidrs:    []string{"::/1", "8000::/1"},
+			expected: []string{"::/0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := newIPSet(t, tt.cidrs...)
+			assert.Equal(t, tt.expected, networkStrings(set.Networks()), "Aggregated networks are not correct")
+		})
+	}
+}
+
+func TestIPSetOperations(t *testing.T) {
+	a := newIPSet(t, "10.0.0.0/16", "2001:db8::/32")
+	b := newIPSet(t, "10.0.128.0/17", "10.1.0.0/24", "2001:db8:1::/48")
+
+	assert.Equal(t, []string{"10.0.0.0/16", "10.1.0.0/24", "2001:db8::/32"}, networkStrings(a.Union(b).Networks()))
+	assert.Equal(t, []string{"10.0.128.0/17", "2001:db8:1::/48"}, networkStrings(a.Intersection(b).Networks()))
+	assert.Equal(t, []string{
+		"10.0.0.0/17",
+		"2001:db8::/48",
+		"2001:db8:2::/47",
+		"2001:db8:4::/46",
+		"2001:db8:8::/45",
+		"2001:db8:10::/44",
+		"2001:db8:20::/43",
+		"2001:db8:40::/42",
+		"2001:db8:80::/41",
+		"2001:db8:100::/40",
+		"2001:db8:200::/39",
+		"2001:db8:400::/38",
+		"2001:db8:800::/37",
+		"2001:db8:1000::/36",
+		"2001:db8:2000::/35",
+		"2001:db8:4000::/34",
+		"2001:db8:8000::/33",
+	}, networkStrings(a.Difference(b).Networks()))
+	assert.Equal(t, []string{"10.0.0.0/17", "10.1.0.0/24"}, networkStrings(
+		newIPSet(t, "10.0.0.0/16").SymmetricDifference(newIPSet(t, "10.0.128.0/17", "10.1.0.0/24")).Networks()))
+
+	assert.True(t, a.Difference(a).IsEmpty(), "A set minus itself should be empty")
+	assert.True(t, a.Union(b).Equal(b.Union(a)), "Union should be commutative")
+}
+
+func TestIPSetDifferenceAtAddressSpaceEdges(t *testing.T) {
+	set := newIPSet(t, "0.0.0.0/0").Difference(newIPSet(t, "0.0.0.0/32", "255.255.255.255/32"))
+	ranges := set.Ranges()
+	assert.Len(t, ranges, 1)
+	assert.Equal(t, net.ParseIP("0.0.0.1").To4(), ranges[0].First)
+	assert.Equal(t, net.ParseIP("255.255.255.254").To4(), ranges[0].Last)
+}
+
+func TestIPSetGetAddressCount(t *testing.T) {
+	set := newIPSet(t, "10.0.0.0/16", "10.0.0.0/24", "2001:db8::/64")
+	expected, _ := new(big.Int).SetString("18446744073709617152", 10)
+	assert.Equal(t, expected, set.GetAddressCount(), "Address count is not correct")
+}
+
+func TestIPSetFamilies(t *testing.T) {
+	set := newIPSet(t, "2001:db8::/32", "10.0.0.0/8", "192.168.0.0/16")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.0.0/16"}, networkStrings(set.IPv4().Networks()))
+	assert.Equal(t, []string{"2001:db8::/32"}, networkStrings(set.IPv6().Networks()))
+	assert.True(t, newIPSet(t, "10.0.0.0/8").IPv6().IsEmpty(), "IPv4 only set should have no IPv6 addresses")
+}
+
+func TestIPSetContainsAddress(t *testing.T) {
+	set := newIPSet(t, "10.0.0.0/16", "192.168.0.0/24", "2001:db8::/32")
+
+	tests := []struct {
+		ip       string
+		contains bool
+	}{
+		{ip: "10.0.14.5", contains: true},
+		{ip: "10.1.0.0", contains: false},
+		{ip: "192.168.0.255", contains: true},
+		{ip: "2001:db8:1234::1", contains: true},
+		{ip: "2001:db9::", contains: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip, err := core.ParseAddress(tt.ip)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contains, set.ContainsAddress(ip))
+		})
+	}
+}
+
+func TestIPSetContainsNetwork(t *testing.T) {
+	set := newIPSet(t, "10.0.0.0/25", "10.0.0.128/25", "10.0.2.0/24", "2001:db8::/32")
+
+	tests := []struct {
+		network  string
+		contains bool
+	}{
+		{network: "10.0.0.0/25", contains: true},
+		{network: "10.0.0.0/24", contains: true},
+		{network: "10.0.0.0/23", contains: false},
+		{network: "10.0.2.128/26", contains: true},
+		{network: "10.0.3.0/24", contains: false},
+		{network: "2001:db8:1::/48", contains: true},
+		{network: "::ffff:10.0.0.0/120", contains: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			network, err := core.ParseCIDR(tt.network)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contains, set.ContainsNetwork(network))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"net"
+)
+
+// blockRange is a run of consecutive aligned blocks: the base address of the first block as an
+// integer, and the number of blocks.
+type blockRange struct {
+	first *big.Int
+	count *big.Int
+}
+
+// GetRandomSubnets returns count distinct subnets with the given prefix length, picked uniformly at
+// random from the aligned subnets of the network that do not overlap with exclude. A prefix length
+// that equals the length of the addresses picks single addresses. Every pick is drawn from the whole
+// address space of the network, which may span the full 128 bits of IPv6.
+// It returns an error wrapping ErrInsufficientAddressSpace if there are fewer than count such subnets.
+func GetRandomSubnets(r *rand.Rand, network *net.IPNet, prefixLength int, exclude *IPSet, count int) ([]*net.IPNet, error) {
+	ones, bits := network.Mask.Size()
+	if prefixLength < ones || prefixLength > bits {
+		return nil, fmt.Errorf("%w: a /%d does not fit in %s", ErrInsufficientAddressSpace, prefixLength, FormatNetwork(network))
+	}
+
+	available := NewIPSet(network)
+	if exclude != nil {
+		available = available.Difference(exclude)
+	}
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-prefixLength))
+	blocks, total := alignedBlocks(available.ranges, size)
+	if total.Cmp(big.NewInt(int64(count))) < 0 {
+		return nil, fmt.Errorf("%w: %s holds %s free /%d subnets, %d requested", ErrInsufficientAddressSpace, FormatNetwork(network), total, prefixLength, count)
+	}
+
+	indices := randomIndices(r, total, count)
+	subnets := make([]*net.IPNet, len(indices))
+	for i, index := range indices {
+		subnets[i] = &net.IPNet{
+			IP:   bigIntToIP(blockAt(blocks, index, size), len(networkIP(network))),
+			Mask: net.CIDRMask(prefixLength, bits),
+		}
+	}
+	return subnets, nil
+}
+
+// alignedBlocks returns the runs of blocks of the given size that fit entirely in the ranges, and
+// the total number of blocks.
+func alignedBlocks(ranges []IPRange, size *big.Int) ([]blockRange, *big.Int) {
+	var blocks []blockRange
+	total := new(big.Int)
+	for _, r := range ranges {
+		// The first block starts at the first multiple of size at or after the start of the range,
+		// the blocks end at the last multiple of size at or before the end of the range.
+		first := new(big.Int).SetBytes(r.First)
+		first.Add(first, size).Sub(first, big.NewInt(1))
+		first.Div(first, size)
+		end := new(big.Int).SetBytes(r.Last)
+		end.Add(end, big.NewInt(1)).Div(end, size)
+		count := new(big.Int).Sub(end, first)
+		if count.Sign() <= 0 {
+			continue
+		}
+		blocks = append(blocks, blockRange{first: first.Mul(first, size), count: count})
+		total.Add(total, count)
+	}
+	return blocks, total
+}
+
+// blockAt returns the base address, as an integer, of the block at the given index of the runs.
+func blockAt(blocks []blockRange, index, size *big.Int) *big.Int {
+	index = new(big.Int).Set(index)
+	for _, block := range blocks {
+		if index.Cmp(block.count) < 0 {
+			return index.Mul(index, size).Add(index, block.first)
+		}
+		index.Sub(index, block.count)
+	}
+	panic("index out of range")
+}
+
+// randomIndices returns count distinct integers picked uniformly at random from [0, n).
+func randomIndices(r *rand.Rand, n *big.Int, count int) []*big.Int {
+	// When most of the integers are picked, shuffle them rather than picking until enough distinct
+	// ones come up. Only the first count positions of the shuffle are drawn, and the positions that
+	// were swapped are kept in a map, so that the integers are never all built.
+	if n.Cmp(big.NewInt(2*int64(count))) <= 0 {
+		size := n.Int64()
+		swapped := make(map[int64]int64, count)
+		at := func(i int64) int64 {
+			if v, ok := swapped[i]; ok {
+				return v
+			}
+			return i
+		}
+		indices := make([]*big.Int, count)
+		for i := int64(0); i < int64(count); i++ {
+			j := i + r.Int64N(size-i)
+			indices[i] = big.NewInt(at(j))
+			swapped[j] = at(i)
+		}
+		return indices
+	}
+
+	indices := make([]*big.Int, 0, count)
+	seen := make(map[string]bool, count)
+	for len(indices) < count {
+		index := randomInt(r, n)
+		if key := index.String(); !seen[key] {
+			seen[key] = true
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// randomInt returns an integer picked uniformly at random from [0, n), n must be positive.
+func randomInt(r *rand.Rand, n *big.Int) *big.Int {
+	bitLen := n.BitLen()
+	buf := make([]byte, (bitLen+7)/8)
+	for {
+		var v uint64
+		for i := range buf {
+			if i%8 == 0 {
+				v = r.Uint64()
+			}
+			buf[i] = byte(v)
+			v >>= 8
+		}
+		// Drop the bits above the length of n, so that at least half of the draws are accepted.
+		buf[0] &= byte(1<<(bitLen-8*(len(buf)-1)) - 1)
+		if i := new(big.Int).SetBytes(buf); i.Cmp(n) < 0 {
+			return i
+		}
+	}
+}
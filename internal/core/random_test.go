@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRandomSubnets(t *testing.T) {
+	network, err := core.ParseCIDR("10.0.0.0/16")
+	require.NoError(t, err)
+	exclude := newIPSet(t, "10.0.0.0/17", "10.0.200.5/32")
+
+	subnets, err := core.GetRandomSubnets(rand.New(rand.NewPCG(1, 0)), network, 24, exclude, 50)
+	require.NoError(t, err)
+	assert.Len(t, subnets, 50)
+
+	seen := make(map[string]bool)
+	for _, subnet := range subnets {
+		ones, _ := subnet.Mask.Size()
+		assert.Equal(t, 24, ones, "Subnet %s should be a /24", subnet)
+		assert.True(t, core.ContainsNetwork(network, subnet), "Subnet %s should be in the network", subnet)
+		assert.True(t, exclude.Intersection(core.NewIPSet(subnet)).IsEmpty(), "Subnet %s should not overlap with the exclusions", subnet)
+		assert.Equal(t, subnet.IP.Mask(subnet.Mask), subnet.IP, "Subnet %s should be aligned", subnet)
+		assert.False(t, seen[subnet.String()], "Subnet %s should only be picked once", subnet)
+		seen[subnet.String()] = true
+	}
+}
+
+func TestGetRandomSubnetsSeed(t *testing.T) {
+	network, err := core.ParseCIDR("2001:db8::/32")
+	require.NoError(t, err)
+
+	first, err := core.GetRandomSubnets(rand.New(rand.NewPCG(42, 0)), network, 128, nil, 10)
+	require.NoError(t, err)
+	second, err := core.GetRandomSubnets(rand.New(rand.NewPCG(42, 0)), network, 128, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "The same seed should pick the same addresses")
+}
+
+func TestGetRandomSubnetsAllOfTheSpace(t *testing.T) {
+	network, err := core.ParseCIDR("10.0.0.0/28")
+	require.NoError(t, err)
+
+	subnets, err := core.GetRandomSubnets(rand.New(rand.NewPCG(1, 0)), network, 30, nil, 4)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"10.0.0.0/30", "10.0.0.4/30", "10.0.0.8/30", "10.0.0.12/30"}, networkStrings(subnets))
+
+	_, err = core.GetRandomSubnets(rand.New(rand.NewPCG(1, 0)), network, 30, newIPSet(t, "10.0.0.5/32"), 4)
+	assert.ErrorIs(t, err, core.ErrInsufficientAddressSpace)
+
+	_, err = core.GetRandomSubnets(rand.New(rand.NewPCG(1, 0)), network, 24, nil, 1)
+	assert.ErrorIs(t, err, core.ErrInsufficientAddressSpace)
+}
+
+// TestGetRandomSubnetsFullIPv6 checks that picks span all 128 bits, rather than a range limited to 64 bits.
+func TestGetRandomSubnetsFullIPv6(t *testing.T) {
+	network, err := core.ParseCIDR("::/0")
+	require.NoError(t, err)
+
+	addresses, err := core.GetRandomSubnets(rand.New(rand.NewPCG(7, 0)), network, 128, nil, 64)
+	require.NoError(t, err)
+	var high, low int
+	for _, address := range addresses {
+		if address.IP[0] >= 0x80 {
+			high++
+		}
+		if address.IP[15]&1 == 1 {
+			low++
+		}
+	}
+	assert.Greater(t, high, 16, "About half of the addresses should have the highest bit set")
+	assert.Greater(t, low, 16, "About half of the addresses should have the lowest bit set")
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math/big"
+	"net"
+)
+
+// Relation classifies how two networks relate to each other.
+type Relation int
+
+const (
+	// Disjoint means that the networks have no addresses in common and do not touch.
+	Disjoint Relation = iota
+	// Adjacent means that the networks touch, but do not make up a single supernet together.
+	Adjacent
+	// Mergeable means that the networks touch and together make up exactly their smallest common supernet.
+	Mergeable
+	// Subnet means that the first network is part of the second network.
+	Subnet
+	// Supernet means that the first network contains the second network.
+	Supernet
+	// Equal means that both networks span the same addresses.
+	Equal
+)
+
+func (r Relation) String() string {
+	switch r {
+	case Adjacent:
+		return "adjacent"
+	case Mergeable:
+		return "mergeable"
+	case Subnet:
+		return "subnet"
+	case Supernet:
+		return "supernet"
+	case Equal:
+		return "equal"
+	default:
+		return "disjoint"
+	}
+}
+
+// Relationship describes how two networks relate to each other.
+type Relationship struct {
+	Relation Relation
+	// Intersection is the network that both networks have in common, nil if they have no addresses in common.
+	Intersection *net.IPNet
+	// Gap is the smallest list of CIDR ranges that covers the addresses between the networks,
+	// empty if they overlap or touch.
+	Gap []*net.IPNet
+	// Supernet is the smallest network that contains both networks.
+	Supernet *net.IPNet
+}
+
+// GetRelationship classifies how network a relates to network b. It returns ErrAddressFamilyMismatch if
+// the networks are of different address families, as they have no common supernet.
+func GetRelationship(a, b *net.IPNet) (*Relationship, error) {
+	aOnes, aBits := a.Mask.Size()
+	bOnes, bBits := b.Mask.Size()
+	if aBits != bBits {
+		return nil, ErrAddressFamilyMismatch
+	}
+	aRange := GetNetworkRange(a)
+	bRange := GetNetworkRange(b)
+
+	// The smallest common supernet is limited by the bits that the base addresses have in common.
+	diff := new(big.Int).Xor(new(big.Int).SetBytes(aRange.First), new(big.Int).SetBytes(bRange.First))
+	supernetOnes := min(aOnes, bOnes, aBits-diff.BitLen())
+	mask := net.CIDRMask(supernetOnes, aBits)
+	relationship := &Relationship{
+		Supernet: &net.IPNet{IP: aRange.First.Mask(mask), Mask: mask},
+	}
+
+	switch {
+	case aOnes == bOnes && compareIP(aRange.First, bRange.First) == 0:
+		relationship.Relation = Equal
+		relationship.Intersection = &net.IPNet{IP: aRange.First, Mask: a.Mask}
+	case ContainsNetwork(b, a):
+		relationship.Relation = Subnet
+		relationship.Intersection = &net.IPNet{IP: aRange.First, Mask: a.Mask}
+	case ContainsNetwork(a, b):
+		relationship.Relation = Supernet
+		relationship.Intersection = &net.IPNet{IP: bRange.First, Mask: b.Mask}
+	default:
+		lower, higher := aRange, bRange
+		if compareIP(lower.First, higher.First) > 0 {
+			lower, higher = higher, lower
+		}
+		// Both networks are disjoint, and the lower one cannot end with the last address of the family.
+		first, _ := nextIP(lower.Last)
+		if compareIP(first, higher.First) == 0 {
+			relationship.Relation = Adjacent
+			if aOnes == bOnes && supernetOnes == aOnes-1 {
+				relationship.Relation = Mergeable
+			}
+			break
+		}
+		last, _ := previousIP(higher.First)
+		relationship.Relation = Disjoint
+		relationship.Gap = IPRange{First: first, Last: last}.Networks()
+	}
+	return relationship, nil
+}
@@ -0,0 +1,142 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRelationship(t *testing.T) {
+	tests := []struct {
+		name         string
+		a            string
+		b            string
+		relation     core.Relation
+		intersection string
+		gap          []string
+		supernet     string
+	}{
+		{
+			name:         "Equal networks",
+			a:            "10.0.0.0/16",
+			b:            "10.0.0.0/16",
+			relation:     core.Equal,
+			intersection: "10.0.0.0/16",
+			supernet:     "10.0.0.0/16",
+		},
+		{
+			name:         "Equal networks with a host address",
+			a:            "10.0.0.1/16",
+			b:            "10.0.0.0/16",
+			relation:     core.Equal,
+			intersection: "10.0.0.0/16",
+			supernet:     "10.0.0.0/16",
+		},
+		{
+			name:         "A subnet of the other network",
+			a:            "10.0.128.0/17",
+			b:            "10.0.0.0/16",
+			relation:     core.Subnet,
+			intersection: "10.0.128.0/17",
+			supernet:     "10.0.0.0/16",
+		},
+		{
+			name:         "A supernet of the other network",
+			a:            "2001:db8::/32",
+			b:            "2001:db8:ffff::/48",
+			relation:     core.Supernet,
+			intersection: "2001:db8:ffff::/48",
+			supernet:     "2001:db8::/32",
+		},
+		{
+			name:     "Adjacent networks that make up a supernet",
+			a:        "192.168.1.0/24",
+			b:        "192.168.0.0/24",
+			relation: core.Mergeable,
+			supernet: "192.168.0.0/23",
+		},
+		{
+			name:     "Adjacent networks that cross a supernet boundary",
+			a:        "192.168.1.0/24",
+			b:        "192.168.2.0/24",
+			relation: core.Adjacent,
+			supernet: "192.168.0.0/22",
+		},
+		{
+			name:     "Adjacent networks of a different size",
+			a:        "192.168.0.0/24",
+			b:        "192.168.1.0/25",
+			relation: core.Adjacent,
+			supernet: "192.168.0.0/23",
+		},
+		{
+			name:     "Both halves of the address space",
+			a:        "0.0.0.0/1",
+			b:        "128.0.0.0/1",
+			relation: core.Mergeable,
+			supernet: "0.0.0.0/0",
+		},
+		{
+			name:     "Disjoint networks",
+			a:        "10.0.5.0/24",
+			b:        "10.0.0.0/24",
+			relation: core.Disjoint,
+			gap:      []string{"10.0.1.0/24", "10.0.2.0/23", "10.0.4.0/24"},
+			supernet: "10.0.0.0/21",
+		},
+		{
+			name:     "Disjoint IPv6 networks",
+			a:        "2001:db8::/48",
+			b:        "2001:db8:2::/48",
+			relation: core.Disjoint,
+			gap:      []string{"2001:db8:1::/48"},
+			supernet: "2001:db8::/46",
+		},
+		{
+			name:     "Disjoint addresses at the end of the address space",
+			a:        "255.255.255.255/32",
+			b:        "255.255.255.252/32",
+			relation: core.Disjoint,
+			gap:      []string{"255.255.255.253/32", "255.255.255.254/32"},
+			supernet: "255.255.255.252/30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := core.ParseCIDR(tt.a)
+			require.NoError(t, err)
+			b, err := core.ParseCIDR(tt.b)
+			require.NoError(t, err)
+
+			relationship, err := core.GetRelationship(a, b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.relation, relationship.Relation, "relation is %s", relationship.Relation)
+			if tt.intersection == "" {
+				assert.Nil(t, relationship.Intersection)
+			} else {
+				assert.Equal(t, tt.intersection, core.FormatNetwork(relationship.Intersection))
+			}
+			if tt.gap == nil {
+				assert.Empty(t, relationship.Gap)
+			} else {
+				assert.Equal(t, tt.gap, networkStrings(relationship.Gap))
+			}
+			assert.Equal(t, tt.supernet, core.FormatNetwork(relationship.Supernet))
+			assert.Equal(t, tt.relation != core.Disjoint && tt.relation != core.Adjacent && tt.relation != core.Mergeable, core.Overlaps(a, b))
+		})
+	}
+}
+
+func TestGetRelationshipAddressFamilyMismatch(t *testing.T) {
+	a, err := core.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+	b, err := core.ParseCIDR("::ffff:10.0.0.0/104")
+	require.NoError(t, err)
+
+	_, err = core.GetRelationship(a, b)
+	assert.True(t, errors.Is(err, core.ErrAddressFamilyMismatch), "expected %v, got %v", core.ErrAddressFamilyMismatch, err)
+}
@@ -0,0 +1,87 @@
+package core
+
+import "net"
+
+// Categories of special-purpose address ranges.
+const (
+	SpecialPurposeUnspecified   = "unspecified"
+	SpecialPurposePrivate       = "private"
+	SpecialPurposeShared        = "shared"
+	SpecialPurposeLoopback      = "loopback"
+	SpecialPurposeLinkLocal     = "link-local"
+	SpecialPurposeDocumentation = "documentation"
+	SpecialPurposeBenchmarking  = "benchmarking"
+	SpecialPurposeMulticast     = "multicast"
+	SpecialPurposeReserved      = "reserved"
+	SpecialPurposeProtocol      = "protocol"
+	SpecialPurposeTranslation   = "translation"
+)
+
+// SpecialPurposeRange is an address range registered for a special purpose by IANA.
+type SpecialPurposeRange struct {
+	Network   *net.IPNet
+	Name      string
+	Category  string
+	Reference string
+}
+
+// specialPurposeRanges holds the IPv4 and IPv6 special-purpose address registries, together with the
+// multicast and reserved ranges. More specific ranges come before the ranges that contain them.
+var specialPurposeRanges = []SpecialPurposeRange{
+	{mustParseCIDR("0.0.0.0/8"), "This network", SpecialPurposeUnspecified, "RFC 791"},
+	{mustParseCIDR("10.0.0.0/8"), "Private-Use", SpecialPurposePrivate, "RFC 1918"},
+	{mustParseCIDR("100.64.0.0/10"), "Shared Address Space", SpecialPurposeShared, "RFC 6598"},
+	{mustParseCIDR("127.0.0.0/8"), "Loopback", SpecialPurposeLoopback, "RFC 1122"},
+	{mustParseCIDR("169.254.0.0/16"), "Link Local", SpecialPurposeLinkLocal, "RFC 3927"},
+	{mustParseCIDR("172.16.0.0/12"), "Private-Use", SpecialPurposePrivate, "RFC 1918"},
+	{mustParseCIDR("192.0.0.0/24"), "IETF Protocol Assignments", SpecialPurposeProtocol, "RFC 6890"},
+	{mustParseCIDR("192.0.2.0/24"), "Documentation (TEST-NET-1)", SpecialPurposeDocumentation, "RFC 5737"},
+	{mustParseCIDR("192.88.99.0/24"), "Deprecated 6to4 Relay Anycast", SpecialPurposeTranslation, "RFC 7526"},
+	{mustParseCIDR("192.168.0.0/16"), "Private-Use", SpecialPurposePrivate, "RFC 1918"},
+	{mustParseCIDR("198.18.0.0/15"), "Benchmarking", SpecialPurposeBenchmarking, "RFC 2544"},
+	{mustParseCIDR("198.51.100.0/24"), "Documentation (TEST-NET-2)", SpecialPurposeDocumentation, "RFC 5737"},
+	{mustParseCIDR("203.0.113.0/24"), "Documentation (TEST-NET-3)", SpecialPurposeDocumentation, "RFC 5737"},
+	{mustParseCIDR("224.0.0.0/4"), "Multicast", SpecialPurposeMulticast, "RFC 5771"},
+	{mustParseCIDR("255.255.255.255/32"), "Limited Broadcast", SpecialPurposeReserved, "RFC 919"},
+	{mustParseCIDR("240.0.0.0/4"), "Reserved", SpecialPurposeReserved, "RFC 1112"},
+
+	{mustParseCIDR("::/128"), "Unspecified Address", SpecialPurposeUnspecified, "RFC 4291"},
+	{mustParseCIDR("::1/128"), "Loopback Address", SpecialPurposeLoopback, "RFC 4291"},
+	{mustParseCIDR("::ffff:0:0/96"), "IPv4-mapped Address", SpecialPurposeTranslation, "RFC 4291"},
+	{mustParseCIDR("64:ff9b::/96"), "IPv4-IPv6 Translation", SpecialPurposeTranslation, "RFC 6052"},
+	{mustParseCIDR("64:ff9b:1::/48"), "IPv4-IPv6 Translation", SpecialPurposeTranslation, "RFC 8215"},
+	{mustParseCIDR("100::/64"), "Discard-Only Address Block", SpecialPurposeReserved, "RFC 6666"},
+	{mustParseCIDR("2001:db8::/32"), "Documentation", SpecialPurposeDocumentation, "RFC 3849"},
+	{mustParseCIDR("2001::/23"), "IETF Protocol Assignments", SpecialPurposeProtocol, "RFC 2928"},
+	{mustParseCIDR("2002::/16"), "6to4", SpecialPurposeTranslation, "RFC 3056"},
+	{mustParseCIDR("3fff::/20"), "Documentation", SpecialPurposeDocumentation, "RFC 9637"},
+	{mustParseCIDR("fc00::/7"), "Unique-Local", SpecialPurposePrivate, "RFC 4193"},
+	{mustParseCIDR("fe80::/10"), "Link-Local Unicast", SpecialPurposeLinkLocal, "RFC 4291"},
+	{mustParseCIDR("ff00::/8"), "Multicast", SpecialPurposeMulticast, "RFC 4291"},
+}
+
+// GetSpecialPurposeRanges returns all known special-purpose address ranges.
+func GetSpecialPurposeRanges() []SpecialPurposeRange {
+	ranges := make([]SpecialPurposeRange, len(specialPurposeRanges))
+	copy(ranges, specialPurposeRanges)
+	return ranges
+}
+
+// GetSpecialPurposeRange returns the most specific special-purpose range that contains the given network.
+// It returns false if the network is not part of any special-purpose range.
+func GetSpecialPurposeRange(network *net.IPNet) (SpecialPurposeRange, bool) {
+	for _, specialPurposeRange := range specialPurposeRanges {
+		if ContainsNetwork(specialPurposeRange.Network, network) {
+			return specialPurposeRange, true
+		}
+	}
+	return SpecialPurposeRange{}, false
+}
+
+func mustParseCIDR(network string) *net.IPNet {
+	ip, err := ParseCIDR(network)
+	if err != nil {
+		panic(err)
+	}
+	return ip
+}
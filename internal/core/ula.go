@@ -0,0 +1,68 @@
+package core
+
+import (
+	"crypto/sha1" //nolint:gosec // RFC 4193 specifies SHA-1, it is not used for security.
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ULAGlobalIDLength is the length in bytes of the Global ID of a unique local address prefix.
+const ULAGlobalIDLength = 5
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch (1970).
+const ntpEpochOffset = 2208988800
+
+// ULANetwork is fd00::/8, the locally assigned half of the unique local address range fc00::/7 (RFC 4193).
+var ULANetwork = mustParseCIDR("fd00::/8")
+
+// ParseEUI64 parses a 48-bit MAC address or an EUI-64 identifier and returns it in the modified EUI-64
+// format of RFC 4291 appendix A, that SLAAC uses as interface identifier. A MAC address and the EUI-64
+// identifier made of it result in the same modified EUI-64. Octets may be separated by colons, dashes
+// or dots.
+func ParseEUI64(s string) ([]byte, error) {
+	hardwareAddr, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEUI64, s)
+	}
+	var eui64 []byte
+	switch len(hardwareAddr) {
+	case 8:
+		eui64 = append([]byte{}, hardwareAddr...)
+	case 6:
+		// Insert ff:fe in the middle of the MAC address.
+		eui64 = []byte{hardwareAddr[0], hardwareAddr[1], hardwareAddr[2], 0xff, 0xfe, hardwareAddr[3], hardwareAddr[4], hardwareAddr[5]}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEUI64, s)
+	}
+	// Invert the universal/local bit.
+	eui64[0] ^= 0x02
+	return eui64, nil
+}
+
+// GetULAGlobalID computes a Global ID with the algorithm of RFC 4193 section 3.2.2: the least
+// significant 40 bits of the SHA-1 digest of the time in NTP format followed by an EUI-64 identifier.
+func GetULAGlobalID(t time.Time, eui64 []byte) []byte {
+	key := make([]byte, 8, 8+len(eui64))
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	binary.BigEndian.PutUint64(key, seconds<<32|fraction)
+	key = append(key, eui64...)
+	digest := sha1.Sum(key)
+	return digest[len(digest)-ULAGlobalIDLength:]
+}
+
+// GetULAGlobalIDFromSeed derives a Global ID from a seed, the same seed always results in the same Global ID.
+func GetULAGlobalIDFromSeed(seed string) []byte {
+	digest := sha1.Sum([]byte(seed))
+	return digest[len(digest)-ULAGlobalIDLength:]
+}
+
+// GetULAPrefix returns the fd00::/8 prefix of length /48 that holds the given Global ID.
+func GetULAPrefix(globalID []byte) *net.IPNet {
+	ip := make(net.IP, net.IPv6len)
+	ip[0] = ULANetwork.IP[0]
+	copy(ip[1:], globalID[:ULAGlobalIDLength])
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(48, 128)}
+}
@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEUI64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "Turn a MAC address into a modified EUI-64",
+			input:    "00:1a:2b:3c:4d:5e",
+			expected: "021a2bfffe3c4d5e",
+		},
+		{
+			name:     "Invert the universal/local bit of a locally administered MAC address",
+			input:    "02-1a-2b-3c-4d-5e",
+			expected: "001a2bfffe3c4d5e",
+		},
+		{
+			name:     "Invert the universal/local bit of an EUI-64",
+			input:    "00:1a:2b:ff:fe:3c:4d:5e",
+			expected: "021a2bfffe3c4d5e",
+		},
+		{
+			name:    "Reject an invalid MAC address",
+			input:   "00:1a:2b",
+			wantErr: core.ErrInvalidEUI64,
+		},
+		{
+			name:    "Reject a 20-octet InfiniBand address",
+			input:   "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01",
+			wantErr: core.ErrInvalidEUI64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eui64, err := core.ParseEUI64(tt.input)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hex.EncodeToString(eui64))
+		})
+	}
+}
+
+func TestGetULAGlobalID(t *testing.T) {
+	eui64, err := core.ParseEUI64("00:1a:2b:3c:4d:5e")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected string
+	}{
+		{
+			name:     "Compute the Global ID from a whole second",
+			time:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: "44cb2cadc5",
+		},
+		{
+			name:     "Include the fraction of a second in the NTP timestamp",
+			time:     time.Date(2024, 1, 1, 0, 0, 0, 500000000, time.UTC),
+			expected: "9eaa66bd29",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hex.EncodeToString(core.GetULAGlobalID(tt.time, eui64)))
+		})
+	}
+}
+
+func TestGetULAGlobalIDFromSeed(t *testing.T) {
+	assert.Equal(t, "5b441169bf", hex.EncodeToString(core.GetULAGlobalIDFromSeed("lab")))
+	assert.Equal(t, core.GetULAGlobalIDFromSeed("lab"), core.GetULAGlobalIDFromSeed("lab"))
+	assert.NotEqual(t, core.GetULAGlobalIDFromSeed("lab"), core.GetULAGlobalIDFromSeed("site"))
+}
+
+func TestGetULAPrefix(t *testing.T) {
+	globalID, err := hex.DecodeString("44cb2cadc5")
+	require.NoError(t, err)
+
+	prefix := core.GetULAPrefix(globalID)
+	assert.Equal(t, "fd44:cb2c:adc5::/48", core.FormatNetwork(prefix))
+	assert.True(t, core.ContainsNetwork(core.ULANetwork, prefix))
+}
+
+func TestGetULAPrefixFromMACAndEUI64(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mac, err := core.ParseEUI64("00:1a:2b:3c:4d:5e")
+	require.NoError(t, err)
+	eui64, err := core.ParseEUI64("00:1a:2b:ff:fe:3c:4d:5e")
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		core.FormatNetwork(core.GetULAPrefix(core.GetULAGlobalID(at, mac))),
+		core.FormatNetwork(core.GetULAPrefix(core.GetULAGlobalID(at, eui64))))
+}
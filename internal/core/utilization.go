@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// Utilization describes how much of a network is allocated to the subnets inside it.
+type Utilization struct {
+	Network      *net.IPNet
+	Total        *big.Int
+	Allocated    *big.Int
+	Free         *big.Int
+	FreeNetworks []*net.IPNet
+}
+
+// GetUtilization calculates the utilization of the network by the given allocations. Allocations may
+// overlap each other, but must be part of the network.
+func GetUtilization(network *net.IPNet, allocations []*net.IPNet) (*Utilization, error) {
+	for _, allocation := range allocations {
+		if !ContainsNetwork(network, allocation) {
+			return nil, fmt.Errorf("%s is %w %s", FormatNetwork(allocation), ErrOutsideNetwork, FormatNetwork(network))
+		}
+	}
+
+	free := NewIPSet(network).Difference(NewIPSet(allocations...))
+	total := GetAddressCount(network)
+	freeCount := free.GetAddressCount()
+	return &Utilization{
+		Network:      network,
+		Total:        total,
+		Allocated:    new(big.Int).Sub(total, freeCount),
+		Free:         freeCount,
+		FreeNetworks: free.Networks(),
+	}, nil
+}
+
+// GetUsedPercentage returns the percentage of allocated addresses.
+func (u *Utilization) GetUsedPercentage() float64 {
+	return percentage(u.Allocated, u.Total)
+}
+
+// GetLargestFreeNetwork returns the largest free aligned network, or nil if the network is fully allocated.
+// When there are multiple free networks of the largest size, the first one is returned.
+func (u *Utilization) GetLargestFreeNetwork() *net.IPNet {
+	var largest *net.IPNet
+	for _, network := range u.FreeNetworks {
+		if largest == nil || GetAddressCount(network).Cmp(GetAddressCount(largest)) > 0 {
+			largest = network
+		}
+	}
+	return largest
+}
+
+// GetFreeNetworkCount returns how many aligned networks of the given prefix length fit in the free space.
+func (u *Utilization) GetFreeNetworkCount(prefixLength int) *big.Int {
+	count := big.NewInt(0)
+	for _, network := range u.FreeNetworks {
+		ones, _ := network.Mask.Size()
+		if ones <= prefixLength {
+			count.Add(count, new(big.Int).Lsh(big.NewInt(1), uint(prefixLength-ones)))
+		}
+	}
+	return count
+}
+
+// GetFragmentation returns how fragmented the free space is, as a percentage. It is 0 when all free
+// addresses are part of the largest free network, and approaches 100 as the free space is split into
+// ever smaller networks.
+func (u *Utilization) GetFragmentation() float64 {
+	largest := u.GetLargestFreeNetwork()
+	if largest == nil {
+		return 0
+	}
+	return 100 - percentage(GetAddressCount(largest), u.Free)
+}
+
+// percentage returns part as a percentage of total.
+func percentage(part, total *big.Int) float64 {
+	if total.Sign() == 0 {
+		return 0
+	}
+	ratio := new(big.Float).Quo(new(big.Float).SetInt(part), new(big.Float).SetInt(total))
+	result, _ := ratio.Mul(ratio, big.NewFloat(100)).Float64()
+	return result
+}
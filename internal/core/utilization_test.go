@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseCIDRs(t *testing.T, cidrs ...string) []*net.IPNet {
+	t.Helper()
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		network, err := core.ParseCIDR(cidr)
+		assert.NoError(t, err)
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func TestGetUtilization(t *testing.T) {
+	network := parseCIDRs(t, "10.0.0.0/16")[0]
+	allocations := parseCIDRs(t, "10.0.0.0/20", "10.0.16.0/21", "10.0.64.0/20")
+
+	utilization, err := core.GetUtilization(network, allocations)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(65536), utilization.Total)
+	assert.Equal(t, big.NewInt(10240), utilization.Allocated)
+	assert.Equal(t, big.NewInt(55296), utilization.Free)
+	assert.InDelta(t, 15.625, utilization.GetUsedPercentage(), 0.0001)
+	assert.Equal(t, []string{"10.0.24.0/21", "10.0.32.0/19", "10.0.80.0/20", "10.0.96.0/19", "10.0.128.0/17"},
+		networkStrings(utilization.FreeNetworks))
+	assert.Equal(t, "10.0.128.0/17", utilization.GetLargestFreeNetwork().String())
+	assert.Equal(t, big.NewInt(1), utilization.GetFreeNetworkCount(17))
+	assert.Equal(t, big.NewInt(13), utilization.GetFreeNetworkCount(20))
+	assert.InDelta(t, 40.7407, utilization.GetFragmentation(), 0.0001)
+}
+
+func TestGetUtilizationOfLargeIPv6Network(t *testing.T) {
+	network := parseCIDRs(t, "2001:db8::/32")[0]
+	allocations := parseCIDRs(t, "2001:db8::/33")
+
+	utilization, err := core.GetUtilization(network, allocations)
+	assert.NoError(t, err)
+	assert.InDelta(t, 50, utilization.GetUsedPercentage(), 0.0001)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 95), utilization.Free)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 31), utilization.GetFreeNetworkCount(64))
+	assert.Equal(t, float64(0), utilization.GetFragmentation())
+}
+
+func TestGetUtilizationWithAllocationOutsideNetwork(t *testing.T) {
+	network := parseCIDRs(t, "10.0.0.0/16")[0]
+	_, err := core.GetUtilization(network, parseCIDRs(t, "10.1.0.0/24"))
+	assert.ErrorIs(t, err, core.ErrOutsideNetwork, "Allocations outside of the network should be rejected")
+}
@@ -0,0 +1,347 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/bschaatsbergen/cidr/internal/input"
+)
+
+// Actions that decide whether the exported prefixes are allowed or denied, for the formats that
+// support both.
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+var (
+	// ErrUnknownFormat is returned when an export format does not exist.
+	ErrUnknownFormat = errors.New("unknown export format")
+	// ErrUnknownAction is returned when an action is not one of allow or deny.
+	ErrUnknownAction = errors.New("unknown action")
+)
+
+// Options configures the generated configuration.
+type Options struct {
+	// Name is the name of the generated set, table, chain or list. Formats that need a list per
+	// address family suffix it with -v4 and -v6.
+	Name string
+	// Action is one of ActionAllow or ActionDeny.
+	Action string
+}
+
+// Format generates the configuration for a single target.
+type Format struct {
+	Name        string
+	Description string
+	write       func(w io.Writer, l *list, options Options) error
+}
+
+// list holds the prefixes to export, split by address family.
+type list struct {
+	ipv4 []input.Entry
+	ipv6 []input.Entry
+}
+
+var formats = []Format{
+	{Name: "iptables", Description: "iptables and ip6tables commands that fill a chain", write: writeIptables},
+	{Name: "nftables", Description: "nftables table with an interval set per address family", write: writeNftables},
+	{Name: "ipset", Description: "ipset restore file with a hash:net set per address family", write: writeIpset},
+	{Name: "pf", Description: "pf table definition", write: writePf},
+	{Name: "cisco", Description: "Cisco IOS ip and ipv6 prefix-lists", write: writeCisco},
+	{Name: "juniper", Description: "Junos prefix-list in set commands", write: writeJuniper},
+	{Name: "aws-security-group", Description: "AWS security group IP permissions JSON", write: writeAWSSecurityGroup},
+	{Name: "aws-prefix-list", Description: "AWS managed prefix list JSON, one per address family", write: writeAWSPrefixList},
+	{Name: "nginx", Description: "nginx allow and deny directives", write: writeNginx},
+	{Name: "haproxy", Description: "HAProxy ACL file", write: writeHAProxy},
+}
+
+// Formats returns all export formats.
+func Formats() []Format {
+	return formats
+}
+
+// Write writes the entries in the named format. IPv4 prefixes are written before IPv6 prefixes,
+// each in the order of the entries.
+func Write(w io.Writer, format string, entries []input.Entry, options Options) error {
+	if options.Action != ActionAllow && options.Action != ActionDeny {
+		return fmt.Errorf("%w: %s", ErrUnknownAction, options.Action)
+	}
+	for _, f := range formats {
+		if f.Name == format {
+			return f.write(w, splitFamilies(entries), options)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// Aggregate returns the smallest list of prefixes that covers the same addresses as entries.
+// Labels are dropped, as aggregated prefixes no longer belong to a single entry.
+func Aggregate(entries []input.Entry) []input.Entry {
+	networks := make([]*net.IPNet, len(entries))
+	for i, entry := range entries {
+		networks[i] = entry.Network
+	}
+	aggregated := core.NewIPSet(networks...).Networks()
+	result := make([]input.Entry, len(aggregated))
+	for i, network := range aggregated {
+		result[i] = input.Entry{Network: network, Text: core.FormatNetwork(network)}
+	}
+	return result
+}
+
+func splitFamilies(entries []input.Entry) *list {
+	l := &list{}
+	for _, entry := range entries {
+		if helper.IsIPv4Network(entry.Network) {
+			l.ipv4 = append(l.ipv4, entry)
+		} else {
+			l.ipv6 = append(l.ipv6, entry)
+		}
+	}
+	return l
+}
+
+// families returns the non-empty families of l with the given IPv4 and IPv6 values.
+func (l *list) families(v4, v6 string) []family {
+	var families []family
+	if len(l.ipv4) > 0 {
+		families = append(families, family{value: v4, entries: l.ipv4})
+	}
+	if len(l.ipv6) > 0 {
+		families = append(families, family{value: v6, entries: l.ipv6})
+	}
+	return families
+}
+
+func (l *list) all() []input.Entry {
+	return append(append([]input.Entry{}, l.ipv4...), l.ipv6...)
+}
+
+// family is the part of a list in a single address family, together with the value that
+// identifies the family in the generated configuration.
+type family struct {
+	value   string
+	entries []input.Entry
+}
+
+// errWriter keeps the first error of a sequence of writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...any) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
+
+func writeIptables(w io.Writer, l *list, options Options) error {
+	target := "ACCEPT"
+	if options.Action == ActionDeny {
+		target = "DROP"
+	}
+	ew := &errWriter{w: w}
+	for _, f := range l.families("iptables", "ip6tables") {
+		ew.printf("%s -N %s\n", f.value, options.Name)
+		for _, entry := range f.entries {
+			ew.printf("%s -A %s -s %s%s -j %s\n", f.value, options.Name, core.FormatNetwork(entry.Network), iptablesComment(entry.Label), target)
+		}
+	}
+	return ew.err
+}
+
+func iptablesComment(label string) string {
+	if label == "" {
+		return ""
+	}
+	return fmt.Sprintf(" -m comment --comment %s", shellQuote(label))
+}
+
+func writeNftables(w io.Writer, l *list, options Options) error {
+	ew := &errWriter{w: w}
+	ew.printf("table inet %s {\n", strings.ReplaceAll(options.Name, "-", "_"))
+	for i, f := range l.families("ipv4_addr", "ipv6_addr") {
+		if i > 0 {
+			ew.printf("\n")
+		}
+		ew.printf("\tset %s {\n", nftablesName(options.Name, f.value == "ipv4_addr"))
+		ew.printf("\t\ttype %s\n", f.value)
+		ew.printf("\t\tflags interval\n")
+		ew.printf("\t\tauto-merge\n")
+		ew.printf("\t\telements = {\n")
+		for j, entry := range f.entries {
+			separator := ","
+			if j == len(f.entries)-1 {
+				separator = ""
+			}
+			ew.printf("\t\t\t%s%s\n", core.FormatNetwork(entry.Network), separator)
+		}
+		ew.printf("\t\t}\n")
+		ew.printf("\t}\n")
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+func writeIpset(w io.Writer, l *list, options Options) error {
+	ew := &errWriter{w: w}
+	for _, f := range l.families("inet", "inet6") {
+		name := familyName(options.Name, f.value == "inet")
+		ew.printf("create %s hash:net family %s -exist\n", name, f.value)
+		for _, entry := range f.entries {
+			ew.printf("add %s %s -exist\n", name, core.FormatNetwork(entry.Network))
+		}
+	}
+	return ew.err
+}
+
+func writePf(w io.Writer, l *list, options Options) error {
+	ew := &errWriter{w: w}
+	ew.printf("table <%s> persist {\n", options.Name)
+	for _, entry := range l.all() {
+		ew.printf("\t%s\n", core.FormatNetwork(entry.Network))
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+func writeCisco(w io.Writer, l *list, options Options) error {
+	action := "permit"
+	if options.Action == ActionDeny {
+		action = "deny"
+	}
+	ew := &errWriter{w: w}
+	for _, f := range l.families("ip", "ipv6") {
+		for i, entry := range f.entries {
+			ew.printf("%s prefix-list %s seq %d %s %s\n", f.value, options.Name, (i+1)*5, action, core.FormatNetwork(entry.Network))
+		}
+	}
+	return ew.err
+}
+
+func writeJuniper(w io.Writer, l *list, options Options) error {
+	ew := &errWriter{w: w}
+	for _, entry := range l.all() {
+		ew.printf("set policy-options prefix-list %s %s\n", options.Name, core.FormatNetwork(entry.Network))
+	}
+	return ew.err
+}
+
+type awsIPRange struct {
+	CidrIP      string `json:"CidrIp"`
+	Description string `json:"Description,omitempty"`
+}
+
+type awsIPv6Range struct {
+	CidrIPv6    string `json:"CidrIpv6"`
+	Description string `json:"Description,omitempty"`
+}
+
+type awsIPPermission struct {
+	IPProtocol string         `json:"IpProtocol"`
+	IPRanges   []awsIPRange   `json:"IpRanges"`
+	IPv6Ranges []awsIPv6Range `json:"Ipv6Ranges"`
+}
+
+// writeAWSSecurityGroup writes the IP permissions that allow all traffic from the prefixes, as
+// accepted by the --ip-permissions option of authorize-security-group-ingress.
+func writeAWSSecurityGroup(w io.Writer, l *list, _ Options) error {
+	permission := awsIPPermission{IPProtocol: "-1", IPRanges: []awsIPRange{}, IPv6Ranges: []awsIPv6Range{}}
+	for _, entry := range l.ipv4 {
+		permission.IPRanges = append(permission.IPRanges, awsIPRange{CidrIP: core.FormatNetwork(entry.Network), Description: entry.Label})
+	}
+	for _, entry := range l.ipv6 {
+		permission.IPv6Ranges = append(permission.IPv6Ranges, awsIPv6Range{CidrIPv6: core.FormatNetwork(entry.Network), Description: entry.Label})
+	}
+	return writeJSON(w, []awsIPPermission{permission})
+}
+
+type awsPrefixListEntry struct {
+	Cidr        string `json:"Cidr"`
+	Description string `json:"Description,omitempty"`
+}
+
+type awsPrefixList struct {
+	PrefixListName string               `json:"PrefixListName"`
+	AddressFamily  string               `json:"AddressFamily"`
+	MaxEntries     int                  `json:"MaxEntries"`
+	Entries        []awsPrefixListEntry `json:"Entries"`
+}
+
+// writeAWSPrefixList writes a managed prefix list per address family, each accepted by the
+// --cli-input-json option of create-managed-prefix-list.
+func writeAWSPrefixList(w io.Writer, l *list, options Options) error {
+	prefixLists := []awsPrefixList{}
+	for _, f := range l.families("IPv4", "IPv6") {
+		prefixList := awsPrefixList{
+			PrefixListName: familyName(options.Name, f.value == "IPv4"),
+			AddressFamily:  f.value,
+			MaxEntries:     len(f.entries),
+		}
+		for _, entry := range f.entries {
+			prefixList.Entries = append(prefixList.Entries, awsPrefixListEntry{Cidr: core.FormatNetwork(entry.Network), Description: entry.Label})
+		}
+		prefixLists = append(prefixLists, prefixList)
+	}
+	return writeJSON(w, prefixLists)
+}
+
+// writeNginx writes a directive per prefix. An allow list ends by denying everything else.
+func writeNginx(w io.Writer, l *list, options Options) error {
+	ew := &errWriter{w: w}
+	for _, entry := range l.all() {
+		if entry.Label != "" {
+			ew.printf("%s %s; # %s\n", options.Action, core.FormatNetwork(entry.Network), entry.Label)
+		} else {
+			ew.printf("%s %s;\n", options.Action, core.FormatNetwork(entry.Network))
+		}
+	}
+	if options.Action == ActionAllow {
+		ew.printf("deny all;\n")
+	}
+	return ew.err
+}
+
+// writeHAProxy writes a file for use with "acl <name> src -f <file>".
+func writeHAProxy(w io.Writer, l *list, _ Options) error {
+	ew := &errWriter{w: w}
+	for _, entry := range l.all() {
+		if entry.Label != "" {
+			ew.printf("# %s\n", entry.Label)
+		}
+		ew.printf("%s\n", core.FormatNetwork(entry.Network))
+	}
+	return ew.err
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// familyName suffixes name with the address family, for formats that need a list per family.
+func familyName(name string, ipv4 bool) string {
+	if ipv4 {
+		return name + "-v4"
+	}
+	return name + "-v6"
+}
+
+// nftablesName is the variant of familyName for nftables, whose identifiers cannot hold a '-'.
+func nftablesName(name string, ipv4 bool) string {
+	return strings.ReplaceAll(familyName(name, ipv4), "-", "_")
+}
+
+// shellQuote quotes s for use as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,93 @@
+package export_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/export"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+const list = "10.0.0.0/8      corp\n" +
+	"2001:db8::/32   documentation\n" +
+	"192.168.1.0/24\n" +
+	"172.16.0.0/12   vpn's range\n" +
+	"2001:db8:1::/48\n"
+
+func readList(t *testing.T, list string) []input.Entry {
+	t.Helper()
+	entries, err := input.Read(strings.NewReader(list), "list.txt")
+	assert.NoError(t, err)
+	return entries
+}
+
+// assertGolden compares got with the named golden file, or updates the file with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(got))
+}
+
+func TestWrite(t *testing.T) {
+	entries := readList(t, list)
+	for _, format := range export.Formats() {
+		t.Run(format.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := export.Write(&buf, format.Name, entries, export.Options{Name: "office", Action: export.ActionAllow})
+			assert.NoError(t, err)
+			assertGolden(t, format.Name, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteDeny(t *testing.T) {
+	entries := readList(t, list)
+	for _, format := range []string{"iptables", "cisco", "nginx"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := export.Write(&buf, format, entries, export.Options{Name: "blocked", Action: export.ActionDeny})
+			assert.NoError(t, err)
+			assertGolden(t, format+"-deny", buf.Bytes())
+		})
+	}
+}
+
+func TestWriteSingleFamily(t *testing.T) {
+	entries := readList(t, "2001:db8::/32\n2001:db8:ffff::/48\n")
+	for _, format := range []string{"nftables", "aws-prefix-list"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := export.Write(&buf, format, entries, export.Options{Name: "v6-only", Action: export.ActionAllow})
+			assert.NoError(t, err)
+			assertGolden(t, format+"-ipv6", buf.Bytes())
+		})
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	entries := readList(t, list)
+	var buf bytes.Buffer
+	assert.ErrorIs(t, export.Write(&buf, "netscreen", entries, export.Options{Name: "office", Action: export.ActionAllow}), export.ErrUnknownFormat)
+	assert.ErrorIs(t, export.Write(&buf, "nginx", entries, export.Options{Name: "office", Action: "reject"}), export.ErrUnknownAction)
+}
+
+func TestAggregate(t *testing.T) {
+	entries := readList(t, "10.0.1.0/24\n10.0.0.0/24 web\n2001:db8::/33\n2001:db8:8000::/33\n10.0.0.5\n")
+	var texts []string
+	for _, entry := range export.Aggregate(entries) {
+		texts = append(texts, entry.Text)
+	}
+	assert.Equal(t, []string{"10.0.0.0/23", "2001:db8::/32"}, texts)
+}
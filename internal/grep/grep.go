@@ -0,0 +1,197 @@
+// Package grep selects the lines of text, such as log files, that hold an address in given networks.
+package grep
+
+import (
+	"bufio"
+	"io"
+	"net/netip"
+	"strings"
+	"unicode"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+// maxLineLength is the longest line that can be read, longer lines fail the search.
+const maxLineLength = 16 * 1024 * 1024
+
+// Options configure which lines Grep selects and what it writes.
+type Options struct {
+	// Networks are the networks that addresses are matched against. Lookups in the set are a binary
+	// search over its sorted ranges, so the number of networks barely affects the speed of a search.
+	Networks *core.IPSet
+	// Field is the 1-based field of a line whose addresses are matched, 0 to match any address on the line.
+	Field int
+	// Delimiter separates the fields of a line, fields are separated by whitespace when it is empty.
+	Delimiter string
+	// Invert selects the lines without a matching address.
+	Invert bool
+	// OnlyMatching writes every matching address on its own line, instead of the lines that hold them.
+	OnlyMatching bool
+	// Prefix is written before every line, such as the name of the file followed by a colon.
+	Prefix string
+	// Unmap matches IPv4-mapped IPv6 addresses as the IPv4 addresses they stand for.
+	Unmap bool
+}
+
+// Grep writes the lines of r that hold an address in one of the networks to w, and returns the
+// number of selected lines. Addresses written with a prefix length, such as 10.0.0.1/24, are matched
+// by their address.
+func Grep(r io.Reader, w io.Writer, options Options) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	out := bufio.NewWriter(w)
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		matches := options.match(line)
+		if (len(matches) > 0) == options.Invert {
+			continue
+		}
+		count++
+
+		if !options.OnlyMatching {
+			writeLine(out, options.Prefix, line)
+			continue
+		}
+		for _, match := range matches {
+			writeLine(out, options.Prefix, match)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	return count, out.Flush()
+}
+
+// match returns the address literals of the line, or of the chosen field, that are in one of the networks.
+func (o *Options) match(line string) []string {
+	text := line
+	if o.Field > 0 {
+		var ok bool
+		if text, ok = field(line, o.Field, o.Delimiter); !ok {
+			return nil
+		}
+	}
+
+	// Instead of regular expressions, which are too slow for logs of many gigabytes, the text is split
+	// in runs of the bytes that addresses are written with, and only runs with a dot or colon are parsed.
+	var matches []string
+	for i := 0; i < len(text); {
+		if !isAddressByte(text[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && isAddressByte(text[i]) {
+			i++
+		}
+		// Skip runs that are part of a longer word, such as a hostname or an identifier.
+		if start > 0 && isWordByte(text[start-1]) || i < len(text) && isWordByte(text[i]) {
+			continue
+		}
+		matches = o.matchRun(text[start:i], matches)
+	}
+	return matches
+}
+
+// matchRun appends the addresses in a run of address bytes that are in one of the networks to matches.
+func (o *Options) matchRun(run string, matches []string) []string {
+	if !strings.ContainsAny(run, ".:") {
+		return matches
+	}
+	// Trailing dots and colons belong to the surrounding text, as in "from 10.0.0.1." or "[::1]:".
+	for len(run) > 0 && (run[len(run)-1] == '.' || run[len(run)-1] == ':') && !strings.HasSuffix(run, "::") {
+		run = run[:len(run)-1]
+	}
+	if isAddressLiteral(run) {
+		if addr, err := netip.ParseAddr(run); err == nil {
+			if o.contains(addr) {
+				matches = append(matches, run)
+			}
+			return matches
+		}
+	}
+
+	// An IPv4 address can be followed by a port, such as 10.0.0.1:8080 in load balancer logs.
+	if !strings.Contains(run, ".") {
+		return matches
+	}
+	for rest := run; rest != ""; {
+		var piece string
+		piece, rest, _ = strings.Cut(rest, ":")
+		if !strings.Contains(piece, ".") || !isAddressLiteral(piece) {
+			continue
+		}
+		if addr, err := netip.ParseAddr(piece); err == nil && o.contains(addr) {
+			matches = append(matches, piece)
+		}
+	}
+	return matches
+}
+
+// isAddressLiteral reports whether a run of address bytes has the dots and colons of an IPv4 or IPv6 address,
+// so that timestamps and MAC addresses are not parsed at all.
+func isAddressLiteral(run string) bool {
+	colons := strings.Count(run, ":")
+	if colons == 0 {
+		return strings.Count(run, ".") == 3
+	}
+	return strings.Contains(run, "::") || colons == 7 || colons == 6 && strings.Contains(run, ".")
+}
+
+// field returns the 1-based nth field of the line, fields are separated by whitespace if the delimiter is empty.
+func field(line string, n int, delimiter string) (string, bool) {
+	for i := 1; ; i++ {
+		var text string
+		var found bool
+		if delimiter == "" {
+			line = strings.TrimLeftFunc(line, unicode.IsSpace)
+			if line == "" {
+				return "", false
+			}
+			end := strings.IndexFunc(line, unicode.IsSpace)
+			if end < 0 {
+				end = len(line)
+			}
+			text, line, found = line[:end], line[end:], end < len(line)
+		} else {
+			text, line, found = strings.Cut(line, delimiter)
+		}
+		if i == n {
+			return text, true
+		}
+		if !found {
+			return "", false
+		}
+	}
+}
+
+// contains reports whether the address is in one of the networks. Like ParseAddress, IPv4 addresses are
+// 4 bytes long and IPv6 addresses, including IPv4-mapped ones, 16 bytes long.
+func (o *Options) contains(addr netip.Addr) bool {
+	if o.Unmap {
+		addr = addr.Unmap()
+	}
+	if addr.Is4() {
+		ip := addr.As4()
+		return o.Networks.ContainsAddress(ip[:])
+	}
+	ip := addr.As16()
+	return o.Networks.ContainsAddress(ip[:])
+}
+
+// isAddressByte reports whether c is one of the bytes that IPv4 and IPv6 addresses are written with.
+func isAddressByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' || c == '.' || c == ':'
+}
+
+// isWordByte reports whether c continues a word that a run of address bytes is part of.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func writeLine(w *bufio.Writer, prefix, text string) {
+	w.WriteString(prefix)
+	w.WriteString(text)
+	w.WriteByte('\n')
+}
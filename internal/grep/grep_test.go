@@ -0,0 +1,168 @@
+package grep_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/grep"
+	"github.com/stretchr/testify/assert"
+)
+
+const accessLog = "10.0.1.5 - - [18/Oct/2026:10:00:00 +0000] \"GET / HTTP/1.1\" 200 612\n" +
+	"203.0.113.7 - - [18/Oct/2026:10:00:01 +0000] \"GET /?from=10.9.9.9 HTTP/1.1\" 404 0\n" +
+	"::ffff:192.168.1.20 - - [18/Oct/2026:10:00:02 +0000] \"GET / HTTP/1.1\" 200 612\n" +
+	"2001:db8::42 - - [18/Oct/2026:10:00:03 +0000] \"GET / HTTP/1.1\" 200 612\n" +
+	"10.0.0.300 - - [18/Oct/2026:10:00:04 +0000] \"GET / HTTP/1.1\" 400 0\n"
+
+func TestGrep(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		networks []string
+		options  grep.Options
+		expected []string
+	}{
+		{
+			name:     "Match any address on a line",
+			text:     accessLog,
+			networks: []string{"10.0.0.0/8"},
+			expected: []string{
+				"10.0.1.5 - - [18/Oct/2026:10:00:00 +0000] \"GET / HTTP/1.1\" 200 612",
+				"203.0.113.7 - - [18/Oct/2026:10:00:01 +0000] \"GET /?from=10.9.9.9 HTTP/1.1\" 404 0",
+			},
+		},
+		{
+			name:     "Match the address of a field",
+			text:     accessLog,
+			networks: []string{"10.0.0.0/8"},
+			options:  grep.Options{Field: 1},
+			expected: []string{
+				"10.0.1.5 - - [18/Oct/2026:10:00:00 +0000] \"GET / HTTP/1.1\" 200 612",
+			},
+		},
+		{
+			name:     "Match the address of a delimited field",
+			text:     "a,b,10.1.1.1\nx,10.1.1.2,y\n",
+			networks: []string{"10.0.0.0/8"},
+			options:  grep.Options{Field: 3, Delimiter: ","},
+			expected: []string{"a,b,10.1.1.1"},
+		},
+		{
+			name:     "Select the lines without a matching address",
+			text:     accessLog,
+			networks: []string{"10.0.0.0/8", "2001:db8::/32"},
+			options:  grep.Options{Field: 1, Invert: true},
+			expected: []string{
+				"203.0.113.7 - - [18/Oct/2026:10:00:01 +0000] \"GET /?from=10.9.9.9 HTTP/1.1\" 404 0",
+				"::ffff:192.168.1.20 - - [18/Oct/2026:10:00:02 +0000] \"GET / HTTP/1.1\" 200 612",
+				"10.0.0.300 - - [18/Oct/2026:10:00:04 +0000] \"GET / HTTP/1.1\" 400 0",
+			},
+		},
+		{
+			name:     "Write only the matching addresses",
+			text:     accessLog,
+			networks: []string{"10.0.0.0/8"},
+			options:  grep.Options{OnlyMatching: true, Prefix: "access.log:"},
+			expected: []string{"access.log:10.0.1.5", "access.log:10.9.9.9"},
+		},
+		{
+			name:     "Match IPv6 addresses",
+			text:     accessLog,
+			networks: []string{"2001:db8::/32"},
+			options:  grep.Options{OnlyMatching: true},
+			expected: []string{"2001:db8::42"},
+		},
+		{
+			name:     "IPv4-mapped addresses only match IPv4 networks when unmapped",
+			text:     accessLog,
+			networks: []string{"192.168.0.0/16"},
+			expected: nil,
+		},
+		{
+			name:     "Unmap IPv4-mapped addresses",
+			text:     accessLog,
+			networks: []string{"192.168.0.0/16"},
+			options:  grep.Options{OnlyMatching: true, Unmap: true},
+			expected: []string{"::ffff:192.168.1.20"},
+		},
+		{
+			name:     "Match addresses written with a prefix length",
+			text:     "interface eth0\n ip address 172.16.4.1/22\n",
+			networks: []string{"172.16.0.0/12"},
+			expected: []string{" ip address 172.16.4.1/22"},
+		},
+		{
+			name:     "Match addresses followed by a port",
+			text:     "2026-10-18T10:00:00.186641Z app/lb/50dc6c495c0c9188 10.0.1.5:2817 [2001:db8::7]:443 200\n",
+			networks: []string{"10.0.0.0/8", "2001:db8::/32"},
+			options:  grep.Options{OnlyMatching: true},
+			expected: []string{"10.0.1.5", "2001:db8::7"},
+		},
+		{
+			name:     "Leave out the punctuation around addresses",
+			text:     "connection from 10.0.1.5. (fe80::1%eth0, ::1)\n",
+			networks: []string{"10.0.0.0/8", "::/0"},
+			options:  grep.Options{OnlyMatching: true},
+			expected: []string{"10.0.1.5", "fe80::1", "::1"},
+		},
+		{
+			name:     "Ignore timestamps, MAC addresses, versions and addresses that are part of a word",
+			text:     "10:00:00 00:1a:2b:3c:4d:5e v10.0.0.1 10.0.0.1x 1.2.3.4.5 cafe::babe\n",
+			networks: []string{"0.0.0.0/0", "::/0"},
+			options:  grep.Options{OnlyMatching: true},
+			expected: []string{"cafe::babe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var networks []*net.IPNet
+			for _, cidr := range tt.networks {
+				network, err := core.ParseCIDR(cidr)
+				assert.NoError(t, err)
+				networks = append(networks, network)
+			}
+			tt.options.Networks = core.NewIPSet(networks...)
+
+			var out bytes.Buffer
+			count, err := grep.Grep(strings.NewReader(tt.text), &out, tt.options)
+			assert.NoError(t, err)
+			var lines []string
+			if out.Len() > 0 {
+				lines = strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			}
+			assert.Equal(t, tt.expected, lines)
+			if !tt.options.OnlyMatching {
+				assert.Equal(t, len(tt.expected), count)
+			}
+		})
+	}
+}
+
+func BenchmarkGrep(b *testing.B) {
+	var text strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&text, "%d.%d.%d.%d - - [18/Oct/2026:10:00:00 +0000] \"GET /index.html HTTP/1.1\" 200 612 \"-\" \"Mozilla/5.0 (X11; Linux x86_64)\"\n",
+			i%223+1, i%256, i/256%256, i%254+1)
+	}
+	networks := []*net.IPNet{
+		{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+		{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(16, 32)},
+	}
+	for _, options := range []grep.Options{{}, {Field: 1}} {
+		options.Networks = core.NewIPSet(networks...)
+		b.Run(fmt.Sprintf("field=%d", options.Field), func(b *testing.B) {
+			b.SetBytes(int64(text.Len()))
+			for i := 0; i < b.N; i++ {
+				if _, err := grep.Grep(strings.NewReader(text.String()), io.Discard, options); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
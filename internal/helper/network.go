@@ -4,12 +4,14 @@ import "net"
 
 // isIPv4Network checks if the given network is an IPv4 network.
 // It returns true if the network is an IPv4 network, otherwise false.
+// The address family follows from the length of the mask, so an IPv4-mapped IPv6 network such as
+// ::ffff:10.0.0.0/104 is an IPv6 network.
 func IsIPv4Network(network *net.IPNet) bool {
-	return network.IP.To4() != nil
+	return len(network.Mask) == net.IPv4len
 }
 
 // isIPv6Network checks if the given network is an IPv6 network.
 // It returns true if the network is an IPv6 network, otherwise false.
 func IsIPv6Network(network *net.IPNet) bool {
-	return network.IP.To16() != nil
+	return len(network.Mask) == net.IPv6len
 }
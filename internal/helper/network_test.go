@@ -0,0 +1,46 @@
+package helper_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/helper"
+)
+
+func TestIsIPv4AndIPv6Network(t *testing.T) {
+	tests := []struct {
+		name   string
+		cidr   string
+		isIPv4 bool
+	}{
+		{
+			name:   "IPv4 network",
+			cidr:   "10.0.0.0/8",
+			isIPv4: true,
+		},
+		{
+			name:   "IPv6 network",
+			cidr:   "2001:db8::/32",
+			isIPv4: false,
+		},
+		{
+			name:   "IPv4-mapped IPv6 network",
+			cidr:   "::ffff:10.0.0.0/104",
+			isIPv4: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, network, err := net.ParseCIDR(tt.cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := helper.IsIPv4Network(network); got != tt.isIPv4 {
+				t.Errorf("IsIPv4Network() = %v, want %v", got, tt.isIPv4)
+			}
+			if got := helper.IsIPv6Network(network); got == tt.isIPv4 {
+				t.Errorf("IsIPv6Network() = %v, want %v", got, !tt.isIPv4)
+			}
+		})
+	}
+}
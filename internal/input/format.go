@@ -0,0 +1,90 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the format of a list of prefixes.
+type Format string
+
+// Formats that lists of prefixes can be read from.
+const (
+	// FormatPlain is a prefix per line, optionally followed by a label.
+	FormatPlain Format = "plain"
+	// FormatIPRoute is the output of "ip route" and "ip -6 route" on Linux.
+	FormatIPRoute Format = "ip-route"
+	// FormatCisco is the output of "show ip route" and "show ipv6 route" on Cisco IOS and Arista EOS.
+	FormatCisco Format = "cisco"
+	// FormatBird is the output of "show route" in BIRD.
+	FormatBird Format = "bird"
+	// FormatJunos is the output of "show route terse" in Junos.
+	FormatJunos Format = "junos"
+	// FormatNmap is a list of target specifications in the style of nmap, separated by whitespace.
+	FormatNmap Format = "nmap"
+)
+
+// ErrUnknownFormat is returned when a list format does not exist.
+var ErrUnknownFormat = errors.New("unknown input format")
+
+// routeParsers parse the lines of a routing table into prefixes with their metadata.
+var routeParsers = map[Format]func(lines []string, source string) []Line{
+	FormatIPRoute: parseIPRoute,
+	FormatCisco:   parseCisco,
+	FormatBird:    parseBird,
+	FormatJunos:   parseJunos,
+}
+
+// Formats returns the names of all list formats.
+func Formats() []Format {
+	return []Format{FormatPlain, FormatIPRoute, FormatCisco, FormatBird, FormatJunos, FormatNmap}
+}
+
+// ParseFormat returns the list format with the given name.
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats() {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+}
+
+// LinesFormat reads the prefixes from r in the given format. Routing tables result in a line
+// per route, lines that do not hold a route are skipped. Target specifications result in a line
+// per CIDR range they expand to.
+func LinesFormat(r io.Reader, source string, format Format) ([]Line, error) {
+	if format == FormatPlain || format == "" {
+		return Lines(r, source)
+	}
+	if format == FormatNmap {
+		return parseTargets(r, source)
+	}
+	parse, ok := routeParsers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	var texts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		texts = append(texts, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return parse(texts, source), nil
+}
+
+// ReadFormat reads and parses all prefixes from r in the given format. The first line that
+// fails to parse is returned as a *PositionError.
+func ReadFormat(r io.Reader, source string, format Format) ([]Entry, error) {
+	lines, err := LinesFormat(r, source, format)
+	if err != nil {
+		return nil, err
+	}
+	return parseLines(lines)
+}
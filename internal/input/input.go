@@ -0,0 +1,185 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+const (
+	// Stdin is the name that refers to standard input instead of a file.
+	Stdin = "-"
+	// stdinSource is the source that is reported for lines read from standard input.
+	stdinSource = "stdin"
+)
+
+// Line is a non-empty line read from a list of prefixes, stripped of comments.
+type Line struct {
+	Source string
+	Number int
+	// Column is the 1-based column at which Field starts.
+	Column int
+	// Field is the first whitespace separated field, the prefix or address itself.
+	Field string
+	// Label holds the remaining fields, if any.
+	Label string
+	// NextHop, Interface and Protocol describe the route a prefix was read from, if any.
+	NextHop   string
+	Interface string
+	Protocol  string
+}
+
+// Entry is a prefix read from a list, together with the position it was read from.
+type Entry struct {
+	Network *net.IPNet
+	// Text is the prefix or address as it was written.
+	Text   string
+	Source string
+	Line   int
+	Label  string
+	// NextHop, Interface and Protocol describe the route a prefix was read from, if any.
+	NextHop   string
+	Interface string
+	Protocol  string
+}
+
+// PositionError records the position of a line that could not be read.
+type PositionError struct {
+	Source string
+	Line   int
+	Err    error
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Err)
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// Lines reads all non-empty lines from r. Everything after a '#' is treated as a comment.
+func Lines(r io.Reader, source string) ([]Line, error) {
+	var lines []Line
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		lines = append(lines, Line{
+			Source: source,
+			Number: number,
+			Column: strings.IndexFunc(text, func(r rune) bool { return !unicode.IsSpace(r) }) + 1,
+			Field:  fields[0],
+			Label:  strings.Join(fields[1:], " "),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return lines, nil
+}
+
+// ParseLine parses the field of a line as a CIDR range, or as a single address which results in
+// a network holding only that address.
+func ParseLine(line Line) (*net.IPNet, error) {
+	return ParsePrefix(line.Field)
+}
+
+// ParsePrefix parses s as a CIDR range, or as a single address which results in a network
+// holding only that address.
+func ParsePrefix(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		return core.ParseCIDR(s)
+	}
+	ip, err := core.ParseAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(ip) == net.IPv4len {
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Read reads and parses all prefixes from r. The first line that fails to parse is returned
+// as a *PositionError.
+func Read(r io.Reader, source string) ([]Entry, error) {
+	lines, err := Lines(r, source)
+	if err != nil {
+		return nil, err
+	}
+	return parseLines(lines)
+}
+
+func parseLines(lines []Line) ([]Entry, error) {
+	entries := make([]Entry, 0, len(lines))
+	for _, line := range lines {
+		network, err := ParseLine(line)
+		if err != nil {
+			return nil, &PositionError{Source: line.Source, Line: line.Number, Err: err}
+		}
+		entries = append(entries, Entry{
+			Network:   network,
+			Text:      line.Field,
+			Source:    line.Source,
+			Line:      line.Number,
+			Label:     line.Label,
+			NextHop:   line.NextHop,
+			Interface: line.Interface,
+			Protocol:  line.Protocol,
+		})
+	}
+	return entries, nil
+}
+
+// Open opens the named file for reading, or standard input if the name is Stdin.
+func Open(name string) (io.ReadCloser, error) {
+	if name == Stdin {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// sourceName returns the source that is reported for the named file.
+func sourceName(name string) string {
+	if name == Stdin {
+		return stdinSource
+	}
+	return name
+}
+
+// LinesFile reads all prefixes from the named file in the given format, or from standard input
+// if the name is Stdin.
+func LinesFile(name string, format Format) ([]Line, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LinesFormat(f, sourceName(name), format)
+}
+
+// ReadFile reads and parses all prefixes from the named file in the given format, or from
+// standard input if the name is Stdin.
+func ReadFile(name string, format Format) ([]Entry, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadFormat(f, sourceName(name), format)
+}
@@ -0,0 +1,81 @@
+package input_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	list := "# corporate networks\n" +
+		"10.0.0.0/8   corp  # comment\n" +
+		"\n" +
+		"  2001:db8::/32\tdocumentation\n"
+
+	lines, err := input.Lines(strings.NewReader(list), "networks.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, []input.Line{
+		{Source: "networks.txt", Number: 2, Column: 1, Field: "10.0.0.0/8", Label: "corp"},
+		{Source: "networks.txt", Number: 4, Column: 3, Field: "2001:db8::/32", Label: "documentation"},
+	}, lines)
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Parse an IPv4 CIDR",
+			prefix:   "10.0.0.0/16",
+			expected: "10.0.0.0/16",
+		},
+		{
+			name:     "Parse an IPv4 address as a host prefix",
+			prefix:   "10.0.14.5",
+			expected: "10.0.14.5/32",
+		},
+		{
+			name:     "Parse an IPv6 address as a host prefix",
+			prefix:   "2001:db8::1",
+			expected: "2001:db8::1/128",
+		},
+		{
+			name:    "Parse an invalid prefix",
+			prefix:  "10.0.0.0/33",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := input.ParsePrefix(tt.prefix)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, network.String(), "Parsed prefix is not correct")
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	entries, err := input.Read(strings.NewReader("10.0.0.0/8\n192.168.0.0/16 home\n"), "networks.txt")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "192.168.0.0/16", entries[1].Network.String())
+	assert.Equal(t, "home", entries[1].Label)
+	assert.Equal(t, 2, entries[1].Line)
+
+	_, err = input.Read(strings.NewReader("10.0.0.0/8\nbogus\n"), "networks.txt")
+	var positionErr *input.PositionError
+	assert.True(t, errors.As(err, &positionErr), "Error should be a PositionError")
+	assert.Equal(t, 2, positionErr.Line)
+	assert.ErrorIs(t, err, core.ErrInvalidAddress)
+}
@@ -0,0 +1,377 @@
+package input
+
+import (
+	"net"
+	"strings"
+	"unicode"
+)
+
+// token is a whitespace separated field of a line, with the 1-based column it starts at.
+type token struct {
+	text   string
+	column int
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			tokens = append(tokens, token{text: text[start:i], column: start + 1})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{text: text[start:], column: start + 1})
+	}
+	return tokens
+}
+
+// isIndented reports whether a line starts with whitespace, which marks a continuation of
+// the previous route in most routing table formats.
+func isIndented(text string) bool {
+	return text != "" && unicode.IsSpace(rune(text[0]))
+}
+
+// isAddress reports whether s is an IP address.
+func isAddress(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// isPrefix reports whether s is an address followed by a prefix length.
+func isPrefix(s string) bool {
+	address, length, ok := strings.Cut(s, "/")
+	return ok && length != "" && isAddress(address)
+}
+
+// ipRouteTypes are the route types that can precede the prefix in the output of "ip route".
+var ipRouteTypes = map[string]bool{
+	"unicast":     true,
+	"local":       true,
+	"broadcast":   true,
+	"multicast":   true,
+	"anycast":     true,
+	"blackhole":   true,
+	"unreachable": true,
+	"prohibit":    true,
+	"throw":       true,
+	"nat":         true,
+}
+
+// parseIPRoute parses the output of "ip route" and "ip -6 route". The route type, if any other
+// than unicast, is used as the label. A default route is IPv6 when its next hop is, or when
+// it has a preference, which is only shown for IPv6 routes.
+func parseIPRoute(texts []string, source string) []Line {
+	var lines []Line
+	for i, text := range texts {
+		tokens := tokenize(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		if isIndented(text) {
+			// The next hops of a multipath route: "nexthop via 10.0.0.1 dev eth0 weight 1".
+			if len(lines) > 0 && lines[len(lines)-1].NextHop == "" {
+				fillIPRouteAttributes(&lines[len(lines)-1], tokens)
+			}
+			continue
+		}
+
+		line := Line{Source: source, Number: i + 1}
+		if ipRouteTypes[tokens[0].text] && len(tokens) > 1 {
+			if tokens[0].text != "unicast" {
+				line.Label = tokens[0].text
+			}
+			tokens = tokens[1:]
+		}
+		line.Column = tokens[0].column
+		line.Field = tokens[0].text
+		fillIPRouteAttributes(&line, tokens[1:])
+
+		if line.Field == "default" {
+			line.Field = "0.0.0.0/0"
+			if strings.Contains(line.NextHop, ":") || hasToken(tokens, "pref") {
+				line.Field = "::/0"
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func fillIPRouteAttributes(line *Line, tokens []token) {
+	for j := 0; j+1 < len(tokens); j++ {
+		value := tokens[j+1].text
+		switch tokens[j].text {
+		case "via":
+			// Next hops in another address family are written as "via inet6 fe80::1".
+			if (value == "inet" || value == "inet6") && j+2 < len(tokens) {
+				j++
+				value = tokens[j+1].text
+			}
+			line.NextHop = value
+		case "dev":
+			line.Interface = value
+		case "proto":
+			line.Protocol = value
+		default:
+			continue
+		}
+		j++
+	}
+}
+
+func hasToken(tokens []token, text string) bool {
+	for _, t := range tokens {
+		if t.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// ciscoProtocols maps the route codes of Cisco IOS and Arista EOS to the protocol.
+var ciscoProtocols = map[string]string{
+	"C":  "connected",
+	"L":  "local",
+	"S":  "static",
+	"R":  "rip",
+	"B":  "bgp",
+	"D":  "eigrp",
+	"EX": "eigrp",
+	"O":  "ospf",
+	"i":  "isis",
+	"I":  "isis",
+	"A":  "aggregate",
+	"K":  "kernel",
+	"M":  "mobile",
+}
+
+// isRouteCode reports whether s is one of the short codes in front of a route, such as
+// "O", "IA", "E2" or "S*".
+func isRouteCode(s string) bool {
+	if len(s) > 3 || isAddress(strings.TrimRight(s, "*")) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("*+%>&", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCisco parses the output of "show ip route" and "show ipv6 route" on Cisco IOS, and of
+// "show ip route" on Arista EOS. Routes that are written without a prefix length below a
+// "is subnetted" header get the prefix length of the header.
+func parseCisco(texts []string, source string) []Line {
+	var lines []Line
+	subnetLength := ""
+	for i, text := range texts {
+		tokens := tokenize(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		if first := tokens[0].text; first == "via" || strings.HasPrefix(first, "[") {
+			// The next hop of a route that did not fit on a single line.
+			if len(lines) > 0 && lines[len(lines)-1].NextHop == "" && lines[len(lines)-1].Interface == "" {
+				fillCiscoNextHop(&lines[len(lines)-1], tokens)
+			}
+			continue
+		}
+
+		k := 0
+		for k < len(tokens) && k < 3 && isRouteCode(tokens[k].text) {
+			k++
+		}
+		if k == 0 {
+			// A classful header such as "172.16.0.0/24 is subnetted, 2 subnets".
+			if len(tokens) >= 3 && isPrefix(tokens[0].text) && tokens[1].text == "is" {
+				subnetLength = ""
+				if tokens[2].text == "subnetted," {
+					_, subnetLength, _ = strings.Cut(tokens[0].text, "/")
+				}
+			}
+			continue
+		}
+		if k == len(tokens) {
+			continue
+		}
+
+		field := tokens[k].text
+		if !isPrefix(field) {
+			if !isAddress(field) || subnetLength == "" {
+				continue
+			}
+			field += "/" + subnetLength
+		}
+		line := Line{Source: source, Number: i + 1, Column: tokens[k].column, Field: field}
+		code := strings.TrimRight(tokens[0].text, "*")
+		line.Protocol = code
+		if protocol, ok := ciscoProtocols[code]; ok {
+			line.Protocol = protocol
+		}
+		fillCiscoNextHop(&line, tokens[k+1:])
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fillCiscoNextHop reads the next hop and interface from "via 10.0.0.1, 00:01:23, Ethernet1"
+// or "is directly connected, Ethernet1".
+func fillCiscoNextHop(line *Line, tokens []token) {
+	for j := 0; j+1 < len(tokens); j++ {
+		value := strings.TrimRight(tokens[j+1].text, ",")
+		switch tokens[j].text {
+		case "via":
+			if isAddress(value) {
+				line.NextHop = value
+			} else {
+				line.Interface = value
+			}
+			j++
+		case "connected,":
+			line.Interface = value
+			j++
+		}
+	}
+	if line.NextHop != "" && line.Interface == "" && len(tokens) > 0 {
+		last := strings.TrimRight(tokens[len(tokens)-1].text, ",")
+		// The interface is the last field, unless the route only has an age such as "1d02h".
+		if last != line.NextHop && !isAddress(last) && unicode.IsLetter(rune(last[0])) {
+			line.Interface = last
+		}
+	}
+}
+
+// birdRouteTypes are the route types of BIRD 2 that are used as the label of a route.
+var birdRouteTypes = map[string]bool{
+	"blackhole":   true,
+	"unreachable": true,
+	"prohibit":    true,
+}
+
+// parseBird parses the output of "show route" in BIRD 1 and BIRD 2. The protocol is the name
+// of the protocol instance that provided the route, such as "bgp1". Alternative routes for the
+// same prefix are skipped.
+func parseBird(texts []string, source string) []Line {
+	var lines []Line
+	primary := false
+	for i, text := range texts {
+		tokens := tokenize(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		if isIndented(text) {
+			if strings.Contains(text, "[") {
+				// An alternative route, its next hops do not belong to the primary route.
+				primary = false
+			} else if primary && lines[len(lines)-1].NextHop == "" {
+				fillBirdAttributes(&lines[len(lines)-1], tokens)
+			}
+			continue
+		}
+		if !isPrefix(tokens[0].text) {
+			continue
+		}
+
+		line := Line{Source: source, Number: i + 1, Column: tokens[0].column, Field: tokens[0].text}
+		if len(tokens) > 1 && birdRouteTypes[tokens[1].text] {
+			line.Label = tokens[1].text
+		}
+		for _, t := range tokens[1:] {
+			if name, ok := strings.CutPrefix(t.text, "["); ok {
+				line.Protocol = name
+				break
+			}
+		}
+		fillBirdAttributes(&line, tokens[1:])
+		lines = append(lines, line)
+		primary = true
+	}
+	return lines
+}
+
+func fillBirdAttributes(line *Line, tokens []token) {
+	for j := 0; j+1 < len(tokens); j++ {
+		switch tokens[j].text {
+		case "via":
+			line.NextHop = tokens[j+1].text
+		case "on", "dev":
+			line.Interface = tokens[j+1].text
+		default:
+			continue
+		}
+		j++
+	}
+}
+
+// junosProtocols maps the protocol codes of "show route terse" to the protocol.
+var junosProtocols = map[string]string{
+	"A": "aggregate",
+	"B": "bgp",
+	"D": "direct",
+	"I": "isis",
+	"L": "local",
+	"O": "ospf",
+	"R": "rip",
+	"S": "static",
+}
+
+// junosMarkers are the route state and validation markers in front of a prefix.
+const junosMarkers = "*+-?!VIN"
+
+// junosNextHopLabels are the next hops of routes that do not forward to a next hop.
+var junosNextHopLabels = map[string]bool{
+	"Local":   true,
+	"Receive": true,
+	"Reject":  true,
+	"Discard": true,
+}
+
+// parseJunos parses the output of "show route terse" in Junos. Next hops that are not an
+// address are used as the interface, or as the label for local, receive, reject and discard routes.
+func parseJunos(texts []string, source string) []Line {
+	var lines []Line
+	for i, text := range texts {
+		tokens := tokenize(text)
+		k := 0
+		for k < len(tokens) && k < 3 && len(tokens[k].text) == 1 && strings.Contains(junosMarkers, tokens[k].text) {
+			k++
+		}
+		if k >= len(tokens) || !isPrefix(tokens[k].text) {
+			continue
+		}
+
+		line := Line{Source: source, Number: i + 1, Column: tokens[k].column, Field: tokens[k].text}
+		rest := tokens[k+1:]
+		if len(rest) > 0 {
+			line.Protocol = rest[0].text
+			if protocol, ok := junosProtocols[rest[0].text]; ok {
+				line.Protocol = protocol
+			}
+			rest = rest[1:]
+		}
+		fillJunosNextHop(&line, rest)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func fillJunosNextHop(line *Line, tokens []token) {
+	for _, t := range tokens {
+		switch hop, active := strings.CutPrefix(t.text, ">"); {
+		case junosNextHopLabels[hop]:
+			line.Label = strings.ToLower(hop)
+		case isAddress(hop):
+			line.NextHop = hop
+		case active:
+			line.Interface = hop
+		default:
+			continue
+		}
+		return
+	}
+}
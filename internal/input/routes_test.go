@@ -0,0 +1,189 @@
+package input_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinesFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   input.Format
+		output   string
+		expected []input.Line
+	}{
+		{
+			name:   "Linux ip route",
+			format: input.FormatIPRoute,
+			output: "default via 192.168.1.1 dev eth0 proto dhcp src 192.168.1.10 metric 100\n" +
+				"10.0.0.0/8 via 10.1.1.1 dev eth1 proto static\n" +
+				"192.168.1.0/24 dev eth0 proto kernel scope link src 192.168.1.10 metric 100\n" +
+				"blackhole 10.99.0.0/16 proto static\n" +
+				"10.1.0.0/16 proto static\n" +
+				"\tnexthop via 10.0.0.1 dev eth0 weight 1\n" +
+				"\tnexthop via 10.0.0.2 dev eth1 weight 1\n" +
+				"172.16.0.0/12 via inet6 fe80::1 dev eth2 proto bgp\n",
+			expected: []input.Line{
+				{Number: 1, Column: 1, Field: "0.0.0.0/0", NextHop: "192.168.1.1", Interface: "eth0", Protocol: "dhcp"},
+				{Number: 2, Column: 1, Field: "10.0.0.0/8", NextHop: "10.1.1.1", Interface: "eth1", Protocol: "static"},
+				{Number: 3, Column: 1, Field: "192.168.1.0/24", Interface: "eth0", Protocol: "kernel"},
+				{Number: 4, Column: 11, Field: "10.99.0.0/16", Label: "blackhole", Protocol: "static"},
+				{Number: 5, Column: 1, Field: "10.1.0.0/16", NextHop: "10.0.0.1", Interface: "eth0", Protocol: "static"},
+				{Number: 8, Column: 1, Field: "172.16.0.0/12", NextHop: "fe80::1", Interface: "eth2", Protocol: "bgp"},
+			},
+		},
+		{
+			name:   "Linux ip -6 route",
+			format: input.FormatIPRoute,
+			output: "::1 dev lo proto kernel metric 256 pref medium\n" +
+				"2001:db8::/64 dev eth0 proto ra metric 100 pref medium\n" +
+				"default dev wg0 proto static metric 1024 pref medium\n",
+			expected: []input.Line{
+				{Number: 1, Column: 1, Field: "::1", Interface: "lo", Protocol: "kernel"},
+				{Number: 2, Column: 1, Field: "2001:db8::/64", Interface: "eth0", Protocol: "ra"},
+				{Number: 3, Column: 1, Field: "::/0", Interface: "wg0", Protocol: "static"},
+			},
+		},
+		{
+			name:   "Cisco IOS show ip route",
+			format: input.FormatCisco,
+			output: "Codes: L - local, C - connected, S - static, R - RIP, M - mobile, B - BGP\n" +
+				"       D - EIGRP, EX - EIGRP external, O - OSPF, IA - OSPF inter area\n" +
+				"\n" +
+				"Gateway of last resort is 10.0.0.1 to network 0.0.0.0\n" +
+				"\n" +
+				"S*    0.0.0.0/0 [1/0] via 10.0.0.1\n" +
+				"      10.0.0.0/8 is variably subnetted, 4 subnets, 3 masks\n" +
+				"C        10.0.0.0/24 is directly connected, GigabitEthernet0/0\n" +
+				"O IA     10.2.0.0/16 [110/3] via 10.0.0.1, 00:01:23, GigabitEthernet0/0\n" +
+				"B        192.168.0.0/16 [20/0] via 172.16.0.1, 1d02h\n" +
+				"      172.16.0.0/24 is subnetted, 2 subnets\n" +
+				"D EX     172.16.1.0 [170/2560] via 10.0.0.3, 00:10:01, GigabitEthernet0/1\n" +
+				"O E2     10.3.0.0/16\n" +
+				"           [110/20] via 10.0.0.4, 00:00:12, GigabitEthernet0/2\n",
+			expected: []input.Line{
+				{Number: 6, Column: 7, Field: "0.0.0.0/0", NextHop: "10.0.0.1", Protocol: "static"},
+				{Number: 8, Column: 10, Field: "10.0.0.0/24", Interface: "GigabitEthernet0/0", Protocol: "connected"},
+				{Number: 9, Column: 10, Field: "10.2.0.0/16", NextHop: "10.0.0.1", Interface: "GigabitEthernet0/0", Protocol: "ospf"},
+				{Number: 10, Column: 10, Field: "192.168.0.0/16", NextHop: "172.16.0.1", Protocol: "bgp"},
+				{Number: 12, Column: 10, Field: "172.16.1.0/24", NextHop: "10.0.0.3", Interface: "GigabitEthernet0/1", Protocol: "eigrp"},
+				{Number: 13, Column: 10, Field: "10.3.0.0/16", NextHop: "10.0.0.4", Interface: "GigabitEthernet0/2", Protocol: "ospf"},
+			},
+		},
+		{
+			name:   "Cisco IOS show ipv6 route",
+			format: input.FormatCisco,
+			output: "IPv6 Routing Table - default - 3 entries\n" +
+				"C   2001:DB8:1::/64 [0/0]\n" +
+				"     via GigabitEthernet0/0, directly connected\n" +
+				"S   ::/0 [1/0]\n" +
+				"     via 2001:DB8:1::FFFF\n",
+			expected: []input.Line{
+				{Number: 2, Column: 5, Field: "2001:DB8:1::/64", Interface: "GigabitEthernet0/0", Protocol: "connected"},
+				{Number: 4, Column: 5, Field: "::/0", NextHop: "2001:DB8:1::FFFF", Protocol: "static"},
+			},
+		},
+		{
+			name:   "Arista EOS show ip route",
+			format: input.FormatCisco,
+			output: "VRF: default\n" +
+				"Gateway of last resort:\n" +
+				" S        0.0.0.0/0 [1/0] via 10.0.0.1, Ethernet1\n" +
+				"\n" +
+				" B E      10.1.0.0/16 [200/0] via 10.0.0.2, Ethernet1\n" +
+				" O E2     10.2.0.0/16 [110/1] via 10.0.0.3, Ethernet2\n" +
+				"                              via 10.0.0.4, Ethernet3\n",
+			expected: []input.Line{
+				{Number: 3, Column: 11, Field: "0.0.0.0/0", NextHop: "10.0.0.1", Interface: "Ethernet1", Protocol: "static"},
+				{Number: 5, Column: 11, Field: "10.1.0.0/16", NextHop: "10.0.0.2", Interface: "Ethernet1", Protocol: "bgp"},
+				{Number: 6, Column: 11, Field: "10.2.0.0/16", NextHop: "10.0.0.3", Interface: "Ethernet2", Protocol: "ospf"},
+			},
+		},
+		{
+			name:   "BIRD 2 show route",
+			format: input.FormatBird,
+			output: "BIRD 2.0.8 ready.\n" +
+				"Table master4:\n" +
+				"0.0.0.0/0            unicast [kernel1 10:00:00.000] * (10)\n" +
+				"\tvia 192.168.1.1 on eth0\n" +
+				"192.168.0.0/16       unicast [bgp1 10:00:00.000] * (100) [AS65001i]\n" +
+				"\tvia 10.0.0.2 on eth1\n" +
+				"                     unicast [bgp2 10:00:01.000] (100) [AS65002i]\n" +
+				"\tvia 10.0.0.3 on eth1\n" +
+				"10.9.0.0/16          blackhole [static1 10:00:00.000] * (200)\n" +
+				"Table master6:\n" +
+				"2001:db8::/48        unicast [bgp1 10:00:00.000] * (100) [AS65001i]\n" +
+				"\tvia fe80::1 on eth1\n",
+			expected: []input.Line{
+				{Number: 3, Column: 1, Field: "0.0.0.0/0", NextHop: "192.168.1.1", Interface: "eth0", Protocol: "kernel1"},
+				{Number: 5, Column: 1, Field: "192.168.0.0/16", NextHop: "10.0.0.2", Interface: "eth1", Protocol: "bgp1"},
+				{Number: 9, Column: 1, Field: "10.9.0.0/16", Label: "blackhole", Protocol: "static1"},
+				{Number: 11, Column: 1, Field: "2001:db8::/48", NextHop: "fe80::1", Interface: "eth1", Protocol: "bgp1"},
+			},
+		},
+		{
+			name:   "BIRD 1 show route",
+			format: input.FormatBird,
+			output: "0.0.0.0/0          via 192.168.1.1 on eth0 [kernel1 2020-01-01] * (10)\n" +
+				"10.0.0.0/24        dev eth1 [direct1 2020-01-01] * (240)\n" +
+				"192.168.0.0/16     via 10.0.0.2 on eth1 [bgp1 2020-01-01] * (100) [AS65001i]\n" +
+				"                   via 10.0.0.3 on eth1 [bgp2 2020-01-01] (100) [AS65002i]\n",
+			expected: []input.Line{
+				{Number: 1, Column: 1, Field: "0.0.0.0/0", NextHop: "192.168.1.1", Interface: "eth0", Protocol: "kernel1"},
+				{Number: 2, Column: 1, Field: "10.0.0.0/24", Interface: "eth1", Protocol: "direct1"},
+				{Number: 3, Column: 1, Field: "192.168.0.0/16", NextHop: "10.0.0.2", Interface: "eth1", Protocol: "bgp1"},
+			},
+		},
+		{
+			name:   "Junos show route terse",
+			format: input.FormatJunos,
+			output: "inet.0: 10 destinations, 11 routes (10 active, 0 holddown, 0 hidden)\n" +
+				"+ = Active Route, - = Last Active, * = Both\n" +
+				"\n" +
+				"A V Destination        P Prf   Metric 1   Metric 2  Next hop        AS path\n" +
+				"* ? 0.0.0.0/0          S   5                        >10.0.0.1\n" +
+				"* ? 10.0.0.0/24        D   0                        >ge-0/0/0.0\n" +
+				"* ? 10.0.0.2/32        L   0                         Local\n" +
+				"* ? 192.168.0.0/16     B 170        100            >10.0.0.2       65001 I\n" +
+				"                       B 170        100             10.0.0.3       65002 I\n" +
+				"\n" +
+				"inet6.0: 1 destinations, 1 routes (1 active, 0 holddown, 0 hidden)\n" +
+				"* 2001:db8::/48        B 170        100            >fe80::1        65001 I\n",
+			expected: []input.Line{
+				{Number: 5, Column: 5, Field: "0.0.0.0/0", NextHop: "10.0.0.1", Protocol: "static"},
+				{Number: 6, Column: 5, Field: "10.0.0.0/24", Interface: "ge-0/0/0.0", Protocol: "direct"},
+				{Number: 7, Column: 5, Field: "10.0.0.2/32", Label: "local", Protocol: "local"},
+				{Number: 8, Column: 5, Field: "192.168.0.0/16", NextHop: "10.0.0.2", Protocol: "bgp"},
+				{Number: 12, Column: 3, Field: "2001:db8::/48", NextHop: "fe80::1", Protocol: "bgp"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := input.LinesFormat(strings.NewReader(tt.output), "routes.txt", tt.format)
+			assert.NoError(t, err)
+			for i := range tt.expected {
+				tt.expected[i].Source = "routes.txt"
+			}
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+}
+
+func TestReadFormat(t *testing.T) {
+	entries, err := input.ReadFormat(strings.NewReader("10.0.0.0/8 via 10.1.1.1 dev eth1 proto static\n"), "routes.txt", input.FormatIPRoute)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "10.0.0.0/8", entries[0].Network.String())
+	assert.Equal(t, "10.1.1.1", entries[0].NextHop)
+
+	_, err = input.ReadFormat(strings.NewReader("10.0.0.0/33 dev eth0\n"), "routes.txt", input.FormatIPRoute)
+	assert.ErrorContains(t, err, "routes.txt:1: invalid CIDR range: 10.0.0.0/33")
+
+	_, err = input.ParseFormat("cisco-nxos")
+	assert.ErrorIs(t, err, input.ErrUnknownFormat)
+}
@@ -0,0 +1,230 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+// maxTargetRanges limits the number of ranges that a target specification expands to, such as the
+// 65,536 ranges of 10.*.*.1, so that a typo cannot exhaust memory.
+const maxTargetRanges = 1 << 20
+
+// ErrInvalidTarget is returned when a string cannot be parsed as a target specification.
+var ErrInvalidTarget = errors.New("invalid target specification")
+
+// octetRange is an inclusive range of values of an IPv4 octet.
+type octetRange struct {
+	first, last int
+}
+
+// ParseTarget parses a target specification in the style of nmap and returns the smallest sorted
+// list of CIDR ranges that covers the addresses it specifies. Next to CIDR ranges and addresses, it
+// accepts IPv4 addresses of which every octet is a list of values and ranges, such as 10.0.0.1,3,7-9,
+// with * for all values, such as 192.168.*.1, and address ranges, such as 10.0.0.1-10.0.0.20 or
+// 2001:db8::1-2001:db8::ff. An IPv4 specification may end in a prefix length, which widens every
+// address it specifies to the CIDR range of that length, such as 10.0.1-5.0/24.
+func ParseTarget(spec string) ([]*net.IPNet, error) {
+	address, prefix, hasPrefix := strings.Cut(spec, "/")
+
+	if first, last, isRange := strings.Cut(address, "-"); isRange && !hasPrefix {
+		if r, ok := parseAddressRange(first, last); ok {
+			return r.Networks(), nil
+		}
+	}
+	if !strings.ContainsAny(address, "*,-") {
+		network, err := ParsePrefix(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, spec)
+		}
+		return []*net.IPNet{network}, nil
+	}
+
+	octets, err := parseOctets(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, spec)
+	}
+	mask := net.CIDRMask(32, 32)
+	if hasPrefix {
+		prefixLength, err := strconv.Atoi(prefix)
+		if err != nil || prefixLength < 0 || prefixLength > 32 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, spec)
+		}
+		mask = net.CIDRMask(prefixLength, 32)
+	}
+
+	ranges, ok := expandOctets(octets)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expands to more than %d ranges", ErrInvalidTarget, spec, maxTargetRanges)
+	}
+	networks := make([]*net.IPNet, 0, len(ranges))
+	for _, r := range ranges {
+		// The addresses of a range, widened to the prefix length, make up a range themselves.
+		for i := range r.First {
+			r.First[i] &= mask[i]
+			r.Last[i] |= ^mask[i]
+		}
+		networks = append(networks, r.Networks()...)
+	}
+	return core.NewIPSet(networks...).Networks(), nil
+}
+
+// parseAddressRange parses a range of two addresses of the same address family.
+func parseAddressRange(first, last string) (core.IPRange, bool) {
+	firstIP, err := core.ParseAddress(first)
+	if err != nil {
+		return core.IPRange{}, false
+	}
+	lastIP, err := core.ParseAddress(last)
+	if err != nil || len(firstIP) != len(lastIP) {
+		return core.IPRange{}, false
+	}
+	for i := range firstIP {
+		if firstIP[i] != lastIP[i] {
+			if firstIP[i] > lastIP[i] {
+				return core.IPRange{}, false
+			}
+			break
+		}
+	}
+	return core.IPRange{First: firstIP, Last: lastIP}, true
+}
+
+// parseOctets parses the octets of an IPv4 address in which every octet is a comma separated list
+// of values and ranges, or *. Ranges may leave out their first or last value, such as -9 or 200-.
+func parseOctets(address string) ([4][]octetRange, error) {
+	var octets [4][]octetRange
+	parts := strings.Split(address, ".")
+	if len(parts) != len(octets) {
+		return octets, ErrInvalidTarget
+	}
+	for i, part := range parts {
+		if part == "*" {
+			octets[i] = []octetRange{{first: 0, last: 255}}
+			continue
+		}
+		for _, item := range strings.Split(part, ",") {
+			first, last, isRange := strings.Cut(item, "-")
+			r := octetRange{first: 0, last: 255}
+			var err error
+			if first != "" || !isRange {
+				if r.first, err = parseOctet(first); err != nil {
+					return octets, err
+				}
+				r.last = r.first
+			}
+			if isRange {
+				r.last = 255
+				if last != "" {
+					if r.last, err = parseOctet(last); err != nil {
+						return octets, err
+					}
+				}
+			}
+			if r.first > r.last {
+				return octets, ErrInvalidTarget
+			}
+			octets[i] = append(octets[i], r)
+		}
+	}
+	return octets, nil
+}
+
+func parseOctet(s string) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil || value < 0 || value > 255 || strings.HasPrefix(s, "+") {
+		return 0, ErrInvalidTarget
+	}
+	return value, nil
+}
+
+// expandOctets returns the address ranges that the octets specify. Every combination of values of the
+// octets before the last octet that does not span all values results in a range per range of that octet.
+// It returns false if that results in more than maxTargetRanges ranges.
+func expandOctets(octets [4][]octetRange) ([]core.IPRange, bool) {
+	last := -1
+	for i, ranges := range octets {
+		if len(ranges) != 1 || ranges[0] != (octetRange{first: 0, last: 255}) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return []core.IPRange{{First: net.IP{0, 0, 0, 0}, Last: net.IP{255, 255, 255, 255}}}, true
+	}
+
+	count := len(octets[last])
+	for _, ranges := range octets[:last] {
+		values := 0
+		for _, r := range ranges {
+			values += r.last - r.first + 1
+		}
+		count *= values
+		if count > maxTargetRanges {
+			return nil, false
+		}
+	}
+
+	result := make([]core.IPRange, 0, count)
+	var walk func(i int, ip net.IP)
+	walk = func(i int, ip net.IP) {
+		if i == last {
+			for _, r := range octets[i] {
+				first, lastIP := append(net.IP(nil), ip...), append(net.IP(nil), ip...)
+				first[i], lastIP[i] = byte(r.first), byte(r.last)
+				for j := i + 1; j < len(ip); j++ {
+					first[j], lastIP[j] = 0, 255
+				}
+				result = append(result, core.IPRange{First: first, Last: lastIP})
+			}
+			return
+		}
+		for _, r := range octets[i] {
+			for value := r.first; value <= r.last; value++ {
+				ip[i] = byte(value)
+				walk(i+1, ip)
+			}
+		}
+	}
+	walk(0, make(net.IP, net.IPv4len))
+	return result, true
+}
+
+// parseTargets reads target specifications from r, separated by whitespace, and returns a line per
+// CIDR range they expand to. The line holds the specification as its label.
+func parseTargets(r io.Reader, source string) ([]Line, error) {
+	var lines []Line
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		for _, tok := range tokenize(text) {
+			networks, err := ParseTarget(tok.text)
+			if err != nil {
+				return nil, &PositionError{Source: source, Line: number, Err: err}
+			}
+			for _, network := range networks {
+				lines = append(lines, Line{
+					Source: source,
+					Number: number,
+					Column: tok.column,
+					Field:  core.FormatNetwork(network),
+					Label:  tok.text,
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return lines, nil
+}
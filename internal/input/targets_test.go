@@ -0,0 +1,121 @@
+package input_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Parse a CIDR range",
+			target:   "10.0.0.0/16",
+			expected: []string{"10.0.0.0/16"},
+		},
+		{
+			name:     "Parse an IPv6 address",
+			target:   "2001:db8::1",
+			expected: []string{"2001:db8::1/128"},
+		},
+		{
+			name:     "Parse a list of values and ranges in the last octet",
+			target:   "10.0.0.1,3,7-9",
+			expected: []string{"10.0.0.1/32", "10.0.0.3/32", "10.0.0.7/32", "10.0.0.8/31"},
+		},
+		{
+			name:     "Parse a range in an octet with a prefix length",
+			target:   "10.0.1-5.0/24",
+			expected: []string{"10.0.1.0/24", "10.0.2.0/23", "10.0.4.0/23"},
+		},
+		{
+			name:     "Parse a wildcard octet",
+			target:   "192.168.0.*",
+			expected: []string{"192.168.0.0/24"},
+		},
+		{
+			name:     "Parse a wildcard in the last octets",
+			target:   "10.1,3.*.*",
+			expected: []string{"10.1.0.0/16", "10.3.0.0/16"},
+		},
+		{
+			name:     "Parse ranges without a first or last value",
+			target:   "10.0.0.-1,254-",
+			expected: []string{"10.0.0.0/31", "10.0.0.254/31"},
+		},
+		{
+			name:     "Parse an IPv4 address range",
+			target:   "10.0.0.10-10.0.0.20",
+			expected: []string{"10.0.0.10/31", "10.0.0.12/30", "10.0.0.16/30", "10.0.0.20/32"},
+		},
+		{
+			name:     "Parse an IPv6 address range",
+			target:   "2001:db8::-2001:db8::1:ffff",
+			expected: []string{"2001:db8::/111"},
+		},
+		{
+			name:    "Reject an octet out of range",
+			target:  "10.0.0.256",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a reversed range",
+			target:  "10.0.0.9-7",
+			wantErr: true,
+		},
+		{
+			name:    "Reject an address range of mixed address families",
+			target:  "10.0.0.1-2001:db8::1",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a wildcard in an IPv6 address",
+			target:  "2001:db8::*",
+			wantErr: true,
+		},
+		{
+			name:    "Reject a target that expands to too many ranges",
+			target:  "*.*.*.1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := input.ParseTarget(tt.target)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, input.ErrInvalidTarget)
+				return
+			}
+			require.NoError(t, err)
+			actual := make([]string, len(networks))
+			for i, network := range networks {
+				actual[i] = core.FormatNetwork(network)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestLinesFormatNmap(t *testing.T) {
+	lines, err := input.LinesFormat(strings.NewReader("# scope\n10.0.0.1,3 2001:db8::/32\n  192.168.0-1.*\n"), "scope.txt", input.FormatNmap)
+	require.NoError(t, err)
+	assert.Equal(t, []input.Line{
+		{Source: "scope.txt", Number: 2, Column: 1, Field: "10.0.0.1/32", Label: "10.0.0.1,3"},
+		{Source: "scope.txt", Number: 2, Column: 1, Field: "10.0.0.3/32", Label: "10.0.0.1,3"},
+		{Source: "scope.txt", Number: 2, Column: 12, Field: "2001:db8::/32", Label: "2001:db8::/32"},
+		{Source: "scope.txt", Number: 3, Column: 3, Field: "192.168.0.0/23", Label: "192.168.0-1.*"},
+	}, lines)
+
+	_, err = input.LinesFormat(strings.NewReader("10.0.0.1\n10.0.0.x\n"), "scope.txt", input.FormatNmap)
+	assert.ErrorContains(t, err, "scope.txt:2: invalid target specification: 10.0.0.x")
+}
@@ -0,0 +1,156 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+// Config configures the severity and options of the rules, keyed by rule ID or name.
+//
+// An example config file:
+//
+//	rules:
+//	  overlap:
+//	    severity: warning
+//	  CIDR006:
+//	    severity: info
+//	  special-purpose:
+//	    allow: [private, shared]
+//	unmap: true
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules" json:"rules"`
+	// Unmap treats IPv4-mapped IPv6 prefixes such as ::ffff:10.0.0.0/104 as the IPv4 prefixes they stand for.
+	Unmap bool `yaml:"unmap" json:"unmap"`
+}
+
+// RuleConfig configures a single rule.
+type RuleConfig struct {
+	// Severity overrides the default severity of the rule, "off" disables it.
+	Severity *Severity `yaml:"severity" json:"severity"`
+	// Allow lists the special-purpose categories that may be used, it only applies to the special-purpose rule.
+	Allow []string `yaml:"allow" json:"allow"`
+	// IgnoreAddresses only reports overlaps between CIDR ranges, not addresses that fall in a CIDR range.
+	// It only applies to the overlap rule.
+	IgnoreAddresses *bool `yaml:"ignore-addresses" json:"ignoreAddresses"`
+	// IgnoreDefaultRoutes does not report overlaps with the default routes 0.0.0.0/0 and ::/0, which
+	// contain every other prefix. It only applies to the overlap rule.
+	IgnoreDefaultRoutes *bool `yaml:"ignore-default-routes" json:"ignoreDefaultRoutes"`
+}
+
+// defaultAllowedCategories are the special-purpose categories that are allowed when the config does not say otherwise.
+var defaultAllowedCategories = []string{core.SpecialPurposePrivate}
+
+// DefaultConfig returns a config that runs every rule with its default severity.
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// LoadConfig reads a YAML (or JSON) config from the named file.
+func LoadConfig(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return config, nil
+}
+
+// ParseConfig reads a YAML (or JSON) config from r and checks that it only refers to known rules and categories.
+func ParseConfig(r io.Reader) (*Config, error) {
+	config := DefaultConfig()
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	categories := make([]string, 0)
+	for _, special := range core.GetSpecialPurposeRanges() {
+		categories = append(categories, special.Category)
+	}
+	for key, ruleConfig := range config.Rules {
+		if _, ok := findRule(key); !ok {
+			return nil, fmt.Errorf("unknown rule: %s", key)
+		}
+		for _, category := range ruleConfig.Allow {
+			if !slices.Contains(categories, category) {
+				return nil, fmt.Errorf("unknown special-purpose category: %s", category)
+			}
+		}
+	}
+	return config, nil
+}
+
+// Merge returns a config that holds the rules of c, with the options that other sets for a rule
+// taking precedence.
+func (c *Config) Merge(other *Config) *Config {
+	merged := &Config{Rules: make(map[string]RuleConfig), Unmap: c.Unmap || other.Unmap}
+	for _, config := range []*Config{c, other} {
+		for _, rule := range Rules {
+			ruleConfig, ok := config.ruleConfig(rule)
+			if !ok {
+				continue
+			}
+			m := merged.Rules[rule.ID]
+			if ruleConfig.Severity != nil {
+				m.Severity = ruleConfig.Severity
+			}
+			if ruleConfig.Allow != nil {
+				m.Allow = ruleConfig.Allow
+			}
+			if ruleConfig.IgnoreAddresses != nil {
+				m.IgnoreAddresses = ruleConfig.IgnoreAddresses
+			}
+			if ruleConfig.IgnoreDefaultRoutes != nil {
+				m.IgnoreDefaultRoutes = ruleConfig.IgnoreDefaultRoutes
+			}
+			merged.Rules[rule.ID] = m
+		}
+	}
+	return merged
+}
+
+// ruleConfig returns the config of the given rule, if any.
+func (c *Config) ruleConfig(rule Rule) (RuleConfig, bool) {
+	for key, ruleConfig := range c.Rules {
+		if found, ok := findRule(key); ok && found.ID == rule.ID {
+			return ruleConfig, true
+		}
+	}
+	return RuleConfig{}, false
+}
+
+func (c *Config) severity(rule Rule) Severity {
+	if ruleConfig, ok := c.ruleConfig(rule); ok && ruleConfig.Severity != nil {
+		return *ruleConfig.Severity
+	}
+	return rule.DefaultSeverity
+}
+
+func (c *Config) allows(rule Rule, category string) bool {
+	allowed := defaultAllowedCategories
+	if ruleConfig, ok := c.ruleConfig(rule); ok && ruleConfig.Allow != nil {
+		allowed = ruleConfig.Allow
+	}
+	return slices.Contains(allowed, category)
+}
+
+func (c *Config) ignoresAddresses(rule Rule) bool {
+	ruleConfig, ok := c.ruleConfig(rule)
+	return ok && ruleConfig.IgnoreAddresses != nil && *ruleConfig.IgnoreAddresses
+}
+
+func (c *Config) ignoresDefaultRoutes(rule Rule) bool {
+	ruleConfig, ok := c.ruleConfig(rule)
+	return ok && ruleConfig.IgnoreDefaultRoutes != nil && *ruleConfig.IgnoreDefaultRoutes
+}
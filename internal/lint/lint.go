@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+	"github.com/bschaatsbergen/cidr/internal/input"
+)
+
+// Finding is a problem found on a line of a list of prefixes.
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Source   string   `json:"source"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Message  string   `json:"message"`
+}
+
+// parsedLine is a line that holds a valid prefix.
+type parsedLine struct {
+	input.Line
+	network *net.IPNet
+}
+
+// Lint checks the given lines against all rules that are enabled in the config, and returns the
+// findings in the order of the lines they were found on.
+func Lint(lines []input.Line, config *Config) []Finding {
+	l := &linter{config: config}
+
+	var parsed []parsedLine
+	for _, line := range lines {
+		network, err := input.ParseLine(line)
+		if err != nil {
+			l.report(RuleInvalidSyntax, line, "%s is not a valid CIDR range or IP address", line.Field)
+			continue
+		}
+		if config.Unmap {
+			network = core.Unmap(network)
+		}
+		parsed = append(parsed, parsedLine{Line: line, network: network})
+		l.checkLine(line, network)
+	}
+	l.checkDuplicates(parsed)
+	l.checkOverlaps(parsed)
+
+	order := make(map[input.Line]int, len(lines))
+	for i, line := range lines {
+		order[line] = i
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return order[l.findings[i].line] < order[l.findings[j].line]
+	})
+
+	findings := make([]Finding, len(l.findings))
+	for i, f := range l.findings {
+		findings[i] = f.Finding
+	}
+	return findings
+}
+
+type linter struct {
+	config   *Config
+	findings []lineFinding
+}
+
+type lineFinding struct {
+	Finding
+	line input.Line
+}
+
+func (l *linter) report(rule Rule, line input.Line, format string, args ...any) {
+	severity := l.config.severity(rule)
+	if severity == SeverityOff {
+		return
+	}
+	l.findings = append(l.findings, lineFinding{
+		Finding: Finding{
+			RuleID:   rule.ID,
+			Rule:     rule.Name,
+			Severity: severity,
+			Source:   line.Source,
+			Line:     line.Number,
+			Column:   line.Column,
+			Message:  fmt.Sprintf(format, args...),
+		},
+		line: line,
+	})
+}
+
+// checkLine runs all rules that only need a single line.
+func (l *linter) checkLine(line input.Line, network *net.IPNet) {
+	address, prefix, isCIDR := strings.Cut(line.Field, "/")
+	ip := net.ParseIP(address)
+
+	if isCIDR && !ip.Equal(network.IP) {
+		l.report(RuleHostBitsSet, line, "%s has host bits set, the network address is %s", line.Field, core.FormatNetwork(network))
+	}
+
+	if strings.Contains(address, ":") && address != core.FormatAddress(ip) {
+		canonical := core.FormatAddress(ip)
+		if isCIDR {
+			canonical += "/" + prefix
+		}
+		l.report(RuleNonCanonical, line, "%s is not in canonical notation, write it as %s", line.Field, canonical)
+	}
+
+	ones, _ := network.Mask.Size()
+	if helper.IsIPv4Network(network) && ones%8 != 0 {
+		l.report(RulePrefixBoundary, line, "%s does not fall on an octet boundary", core.FormatNetwork(network))
+	} else if !helper.IsIPv4Network(network) && ones%4 != 0 {
+		l.report(RulePrefixBoundary, line, "%s does not fall on a nibble boundary", core.FormatNetwork(network))
+	}
+
+	if special, ok := core.GetSpecialPurposeRange(network); ok && !l.config.allows(RuleSpecialPurpose, special.Category) {
+		l.report(RuleSpecialPurpose, line, "%s is in the %s range %s (%s)", core.FormatNetwork(network), special.Name, core.FormatNetwork(special.Network), special.Reference)
+	}
+}
+
+func (l *linter) checkDuplicates(lines []parsedLine) {
+	seen := make(map[string]input.Line, len(lines))
+	for _, line := range lines {
+		key := core.FormatNetwork(line.network)
+		if first, ok := seen[key]; ok {
+			l.report(RuleDuplicate, line.Line, "%s is a duplicate of %s at %s:%d", key, first.Field, first.Source, first.Number)
+			continue
+		}
+		seen[key] = line.Line
+	}
+}
+
+// checkOverlaps reports every prefix that is contained in another, different, prefix. As two prefixes
+// either nest or are disjoint, sorting them by address and then by prefix length puts every prefix
+// right after the prefixes that contain it.
+func (l *linter) checkOverlaps(lines []parsedLine) {
+	sorted := make([]parsedLine, 0, len(lines))
+	for _, line := range lines {
+		if l.config.ignoresAddresses(RuleOverlap) && !strings.Contains(line.Field, "/") {
+			continue
+		}
+		if ones, _ := line.network.Mask.Size(); ones == 0 && l.config.ignoresDefaultRoutes(RuleOverlap) {
+			continue
+		}
+		sorted = append(sorted, line)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return core.CompareNetworks(sorted[i].network, sorted[j].network) < 0
+	})
+
+	var parents []parsedLine
+	for _, line := range sorted {
+		for len(parents) > 0 && !core.Overlaps(parents[len(parents)-1].network, line.network) {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) > 0 {
+			parent := parents[len(parents)-1]
+			if core.FormatNetwork(parent.network) == core.FormatNetwork(line.network) {
+				// Identical prefixes are reported as duplicates.
+				continue
+			}
+			l.report(RuleOverlap, line.Line, "%s overlaps with %s at %s:%d", core.FormatNetwork(line.network), parent.Field, parent.Source, parent.Number)
+		}
+		parents = append(parents, line)
+	}
+}
@@ -0,0 +1,191 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func lintList(t *testing.T, list string, config *lint.Config) []lint.Finding {
+	t.Helper()
+	lines, err := input.Lines(strings.NewReader(list), "networks.txt")
+	assert.NoError(t, err)
+	return lint.Lint(lines, config)
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name          string
+		list          string
+		expectedRules []string
+		expectedLines []int
+	}{
+		{
+			name:          "Valid list only reports the documentation range",
+			list:          "10.0.0.0/16\n10.1.0.0/16\n2001:db8:1::/48 # documentation\n",
+			expectedRules: []string{"CIDR007"},
+			expectedLines: []int{3},
+		},
+		{
+			name:          "Invalid syntax",
+			list:          "10.0.0.0/16\n10.0.0.0/33\n",
+			expectedRules: []string{"CIDR001"},
+			expectedLines: []int{2},
+		},
+		{
+			name:          "Host bits set",
+			list:          "10.0.0.1/16\n",
+			expectedRules: []string{"CIDR002"},
+			expectedLines: []int{1},
+		},
+		{
+			name:          "Duplicate is not reported as an overlap",
+			list:          "10.0.0.0/16\n10.0.0.0/16\n",
+			expectedRules: []string{"CIDR003"},
+			expectedLines: []int{2},
+		},
+		{
+			name:          "Overlap is reported on the more specific CIDR",
+			list:          "10.0.4.0/22\n10.0.0.0/16\n10.0.5.0/24\n",
+			expectedRules: []string{"CIDR004", "CIDR004"},
+			expectedLines: []int{1, 3},
+		},
+		{
+			name:          "Non-canonical IPv6 notation",
+			list:          "FD00:0::/48\n",
+			expectedRules: []string{"CIDR005"},
+			expectedLines: []int{1},
+		},
+		{
+			name:          "Special-purpose range",
+			list:          "127.0.0.0/8\n192.168.0.0/24\n",
+			expectedRules: []string{"CIDR007"},
+			expectedLines: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := lintList(t, tt.list, lint.DefaultConfig())
+			rules := make([]string, 0)
+			lines := make([]int, 0)
+			for _, f := range findings {
+				rules = append(rules, f.RuleID)
+				lines = append(lines, f.Line)
+			}
+			assert.Equal(t, tt.expectedRules, rules, "Reported rules are not correct")
+			assert.Equal(t, tt.expectedLines, lines, "Reported lines are not correct")
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := lint.ParseConfig(strings.NewReader("rules:\n" +
+		"  prefix-boundary:\n" +
+		"    severity: info\n" +
+		"  CIDR004:\n" +
+		"    severity: off\n" +
+		"  special-purpose:\n" +
+		"    allow: [loopback]\n"))
+	assert.NoError(t, err)
+
+	findings := lintList(t, "127.0.0.0/8\n8.8.0.0/16\n8.8.0.0/20\n192.168.0.0/16\n", config)
+	assert.Len(t, findings, 2)
+	assert.Equal(t, "CIDR006", findings[0].RuleID)
+	assert.Equal(t, lint.SeverityInfo, findings[0].Severity)
+	assert.Equal(t, 3, findings[0].Line)
+	assert.Equal(t, "CIDR007", findings[1].RuleID)
+	assert.Equal(t, 4, findings[1].Line)
+
+	_, err = lint.ParseConfig(strings.NewReader("rules:\n  unknown-rule:\n    severity: info\n"))
+	assert.Error(t, err, "Unknown rules should be rejected")
+
+	_, err = lint.ParseConfig(strings.NewReader("rules:\n  overlap:\n    severity: fatal\n"))
+	assert.Error(t, err, "Unknown severities should be rejected")
+}
+
+func TestConfigMerge(t *testing.T) {
+	defaults, err := lint.ParseConfig(strings.NewReader("rules:\n" +
+		"  duplicate:\n" +
+		"    severity: off\n" +
+		"  overlap:\n" +
+		"    ignore-addresses: true\n"))
+	assert.NoError(t, err)
+	overrides, err := lint.ParseConfig(strings.NewReader("rules:\n" +
+		"  CIDR004:\n" +
+		"    severity: warning\n"))
+	assert.NoError(t, err)
+	config := defaults.Merge(overrides)
+
+	findings := lintList(t, "10.0.0.0/16\n10.0.0.1\n10.0.0.0/16\n10.0.1.0/24\n", config)
+	assert.Len(t, findings, 1, "Addresses and duplicates should not be reported")
+	assert.Equal(t, "CIDR004", findings[0].RuleID)
+	assert.Equal(t, lint.SeverityWarning, findings[0].Severity)
+	assert.Equal(t, 4, findings[0].Line)
+}
+
+func TestConfigIgnoreDefaultRoutes(t *testing.T) {
+	list := "0.0.0.0/0\n10.0.0.0/16\n::/0\n2600::/16\n"
+	assert.Len(t, lintList(t, list, lint.DefaultConfig()), 2, "Every prefix should overlap with the default route")
+
+	config, err := lint.ParseConfig(strings.NewReader("rules:\n" +
+		"  overlap:\n" +
+		"    ignore-default-routes: true\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, lintList(t, list, config), "Overlaps with the default routes should not be reported")
+}
+
+func TestConfigUnmap(t *testing.T) {
+	list := "10.0.0.0/8\n::ffff:10.1.0.0/112\n"
+	findings := lintList(t, list, lint.DefaultConfig())
+	assert.Len(t, findings, 1, "IPv4-mapped networks should not overlap with IPv4 networks")
+	assert.Equal(t, "CIDR007", findings[0].RuleID)
+	assert.Contains(t, findings[0].Message, "::ffff:10.1.0.0/112")
+
+	config, err := lint.ParseConfig(strings.NewReader("unmap: true\n"))
+	assert.NoError(t, err)
+	findings = lintList(t, list, config)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "CIDR004", findings[0].RuleID)
+	assert.Equal(t, 2, findings[0].Line)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	findings := lintList(t, "10.0.0.1/16\n", lint.DefaultConfig())
+
+	var buf bytes.Buffer
+	err := lint.WriteSARIF(&buf, lint.ToolInfo{Name: "cidr"}, lint.Rules, findings)
+	assert.NoError(t, err)
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "CIDR002", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "networks.txt", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, result.Locations[0].PhysicalLocation.Region.StartLine)
+}
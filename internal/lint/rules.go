@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of a finding.
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = map[Severity]string{
+	SeverityOff:     "off",
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return severity, nil
+		}
+	}
+	return SeverityOff, fmt.Errorf("unknown severity: %s", name)
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Rule describes a check that is run against every line.
+type Rule struct {
+	ID              string
+	Name            string
+	Description     string
+	DefaultSeverity Severity
+}
+
+var (
+	RuleInvalidSyntax = Rule{
+		ID:              "CIDR001",
+		Name:            "invalid-syntax",
+		Description:     "Line is not a valid CIDR range or IP address",
+		DefaultSeverity: SeverityError,
+	}
+	RuleHostBitsSet = Rule{
+		ID:              "CIDR002",
+		Name:            "host-bits-set",
+		Description:     "CIDR range has bits set beyond its prefix length",
+		DefaultSeverity: SeverityError,
+	}
+	RuleDuplicate = Rule{
+		ID:              "CIDR003",
+		Name:            "duplicate",
+		Description:     "CIDR range occurs more than once",
+		DefaultSeverity: SeverityWarning,
+	}
+	RuleOverlap = Rule{
+		ID:              "CIDR004",
+		Name:            "overlap",
+		Description:     "CIDR range overlaps with another CIDR range",
+		DefaultSeverity: SeverityError,
+	}
+	RuleNonCanonical = Rule{
+		ID:              "CIDR005",
+		Name:            "non-canonical",
+		Description:     "IPv6 address is not written in its canonical (RFC 5952) notation",
+		DefaultSeverity: SeverityWarning,
+	}
+	RulePrefixBoundary = Rule{
+		ID:              "CIDR006",
+		Name:            "prefix-boundary",
+		Description:     "Prefix length does not fall on an octet (IPv4) or nibble (IPv6) boundary, as reverse DNS delegation requires",
+		DefaultSeverity: SeverityOff,
+	}
+	RuleSpecialPurpose = Rule{
+		ID:              "CIDR007",
+		Name:            "special-purpose",
+		Description:     "CIDR range is part of a special-purpose address range that is not allowed",
+		DefaultSeverity: SeverityWarning,
+	}
+
+	// Rules holds all rules, ordered by ID.
+	Rules = []Rule{
+		RuleInvalidSyntax,
+		RuleHostBitsSet,
+		RuleDuplicate,
+		RuleOverlap,
+		RuleNonCanonical,
+		RulePrefixBoundary,
+		RuleSpecialPurpose,
+	}
+)
+
+// findRule returns the rule with the given ID or name.
+func findRule(key string) (Rule, bool) {
+	for _, rule := range Rules {
+		if strings.EqualFold(key, rule.ID) || strings.EqualFold(key, rule.Name) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
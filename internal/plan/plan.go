@@ -0,0 +1,88 @@
+// Package plan reads address plans: YAML files of nested, named blocks of address space,
+// such as an organization that is split up in environments, regions and subnets.
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+// Block is a named block of address space in a plan, with the blocks that it is split up in.
+type Block struct {
+	Name string `yaml:"name"`
+	// CIDR is the CIDR range of the block as it is written in the plan.
+	CIDR string `yaml:"cidr"`
+	// Size optionally declares the size of the block, as a prefix length such as /24 or as a number of addresses.
+	Size        string   `yaml:"size"`
+	Description string   `yaml:"description"`
+	Children    []*Block `yaml:"children"`
+
+	// Line is the line of the block in the plan.
+	Line int `yaml:"-"`
+	// Network is the parsed CIDR range, nil if the block has no valid CIDR range.
+	Network *net.IPNet `yaml:"-"`
+}
+
+// blockFields are the keys that a block can have, any other key is most likely misspelled.
+var blockFields = map[string]bool{"name": true, "cidr": true, "size": true, "description": true, "children": true}
+
+// UnmarshalYAML decodes a block and records the line it starts at. Unknown keys are rejected,
+// so that a misspelled key does not silently leave a part of the plan out of validation.
+func (b *Block) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; !blockFields[key.Value] {
+				return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+			}
+		}
+	}
+	type rawBlock Block
+	if err := node.Decode((*rawBlock)(b)); err != nil {
+		return err
+	}
+	b.Line = node.Line
+	if network, err := core.ParseCIDR(b.CIDR); err == nil {
+		b.Network = network
+	}
+	return nil
+}
+
+// Walk calls fn for the block and all blocks below it, parents before their children.
+func (b *Block) Walk(fn func(block *Block)) {
+	fn(b)
+	for _, child := range b.Children {
+		child.Walk(fn)
+	}
+}
+
+// Load reads a plan from the named file.
+func Load(name string) (*Block, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	root, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return root, nil
+}
+
+// Parse reads a plan from r. The plan is a single block at the top level.
+func Parse(r io.Reader) (*Block, error) {
+	root := &Block{}
+	if err := yaml.NewDecoder(r).Decode(root); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("plan is empty")
+		}
+		return nil, err
+	}
+	return root, nil
+}
@@ -0,0 +1,179 @@
+package plan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	root, err := plan.Parse(strings.NewReader(`name: acme
+cidr: 10.0.0.0/8
+children:
+  - name: prod
+    cidr: 10.0.0.0/16
+    size: 65536
+  - name: dev
+    cidr: invalid
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", root.Name)
+	assert.Equal(t, 1, root.Line)
+	assert.Equal(t, "10.0.0.0/8", root.Network.String())
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, 4, root.Children[0].Line)
+	assert.Equal(t, "65536", root.Children[0].Size)
+	assert.Equal(t, 7, root.Children[1].Line)
+	assert.Nil(t, root.Children[1].Network)
+
+	var names []string
+	root.Walk(func(block *plan.Block) {
+		names = append(names, block.Name)
+	})
+	assert.Equal(t, []string{"acme", "prod", "dev"}, names)
+}
+
+func TestParseError(t *testing.T) {
+	_, err := plan.Parse(strings.NewReader("name: [\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = plan.Parse(strings.NewReader(""))
+	assert.ErrorContains(t, err, "plan is empty")
+
+	_, err = plan.Parse(strings.NewReader(`name: acme
+cidr: 10.0.0.0/8
+children:
+  - name: prod
+    cidr: 10.0.0.0/16
+    childen:
+      - name: outside
+        cidr: 11.0.0.0/16
+`))
+	assert.ErrorContains(t, err, `line 6: unknown field "childen"`)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     string
+		expected []plan.Problem
+	}{
+		{
+			name: "Valid plan",
+			plan: `name: acme
+cidr: 10.0.0.0/8
+children:
+  - name: prod
+    cidr: 10.0.0.0/16
+    size: /16
+    children:
+      - name: eu-west
+        cidr: 10.0.0.0/20
+        size: 4096
+  - name: dev
+    cidr: 10.1.0.0/16
+`,
+		},
+		{
+			name: "Block outside of its parent",
+			plan: `name: acme
+cidr: 10.0.0.0/16
+children:
+  - name: prod
+    cidr: 10.1.0.0/24
+`,
+			expected: []plan.Problem{
+				{Line: 4, Path: "acme/prod", Message: "10.1.0.0/24 is not inside its parent 10.0.0.0/16"},
+			},
+		},
+		{
+			name: "Block of another address family than its parent",
+			plan: `name: acme
+cidr: 10.0.0.0/8
+children:
+  - name: mapped
+    cidr: ::ffff:10.0.0.0/104
+`,
+			expected: []plan.Problem{
+				{Line: 4, Path: "acme/mapped", Message: "::ffff:10.0.0.0/104 is not inside its parent 10.0.0.0/8"},
+			},
+		},
+		{
+			name: "Overlapping siblings",
+			plan: `name: acme
+cidr: 10.0.0.0/8
+children:
+  - name: prod
+    cidr: 10.0.0.0/16
+  - name: dev
+    cidr: 10.1.0.0/16
+  - name: lab
+    cidr: 10.0.128.0/17
+`,
+			expected: []plan.Problem{
+				{Line: 8, Path: "acme/lab", Message: "10.0.128.0/17 overlaps with sibling prod (10.0.0.0/16)"},
+			},
+		},
+		{
+			name: "Blocks that are not canonical",
+			plan: `name: acme
+cidr: 10.0.0.1/8
+children:
+  - name: prod
+    cidr: 2001:DB8::/32
+`,
+			expected: []plan.Problem{
+				{Line: 1, Path: "acme", Message: "10.0.0.1/8 is not canonical, write it as 10.0.0.0/8"},
+				{Line: 4, Path: "acme/prod", Message: "2001:DB8::/32 is not canonical, write it as 2001:db8::/32"},
+				{Line: 4, Path: "acme/prod", Message: "2001:db8::/32 is not inside its parent 10.0.0.0/8"},
+			},
+		},
+		{
+			name: "Blocks that do not match their declared size",
+			plan: `name: acme
+cidr: 10.0.0.0/8
+size: /16
+children:
+  - name: prod
+    cidr: 10.0.0.0/16
+    size: 256
+  - name: dev
+    cidr: 10.1.0.0/16
+    size: large
+`,
+			expected: []plan.Problem{
+				{Line: 1, Path: "acme", Message: "10.0.0.0/8 is /8, but its declared size is /16"},
+				{Line: 5, Path: "acme/prod", Message: "10.0.0.0/16 has 65536 addresses, but its declared size is 256"},
+				{Line: 8, Path: "acme/dev", Message: "invalid size: large"},
+			},
+		},
+		{
+			name: "Blocks without a name or valid CIDR range",
+			plan: `name: acme
+cidr: 10.0.0.0/8
+children:
+  - cidr: 10.0.0.0/16
+  - name: dev
+  - name: lab
+    cidr: 10.0.0.0/33
+`,
+			expected: []plan.Problem{
+				{Line: 4, Path: "acme/(unnamed)", Message: "block has no name"},
+				{Line: 5, Path: "acme/dev", Message: "block has no CIDR range"},
+				{Line: 6, Path: "acme/lab", Message: "invalid CIDR range: 10.0.0.0/33"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := plan.Parse(strings.NewReader(tt.plan))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, plan.Validate(root))
+		})
+	}
+}
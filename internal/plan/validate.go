@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+// Problem is a mistake in a plan.
+type Problem struct {
+	// Line is the line of the block in the plan.
+	Line int `json:"line"`
+	// Path holds the names of the blocks that lead to the block, separated by slashes.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Validate checks that every block has a canonical CIDR range of the declared size, that every block
+// is inside its parent and that sibling blocks do not overlap. It returns the problems in the order of
+// the plan. Containment and overlaps follow the semantics of core.ContainsNetwork and core.Overlaps, so
+// blocks of different address families are never inside or overlapping each other.
+func Validate(root *Block) []Problem {
+	var problems []Problem
+	validate(root, nil, "", &problems)
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+func validate(block, parent *Block, path string, problems *[]Problem) {
+	path = joinPath(path, block.displayName())
+	report := func(format string, a ...any) {
+		*problems = append(*problems, Problem{Line: block.Line, Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if block.Name == "" {
+		report("block has no name")
+	}
+	switch {
+	case block.CIDR == "":
+		report("block has no CIDR range")
+	case block.Network == nil:
+		report("invalid CIDR range: %s", block.CIDR)
+	default:
+		network := block.Network
+		// Compare with the CIDR range as it is written, before it was unmapped.
+		if written, err := core.ParseCIDR(block.CIDR); err == nil && block.CIDR != core.FormatNetwork(written) {
+			report("%s is not canonical, write it as %s", block.CIDR, core.FormatNetwork(written))
+		}
+		if block.Size != "" {
+			if err := checkSize(block); err != nil {
+				report("%s", err)
+			}
+		}
+		if parent != nil && parent.Network != nil && !core.ContainsNetwork(parent.Network, network) {
+			report("%s is not inside its parent %s", core.FormatNetwork(network), core.FormatNetwork(parent.Network))
+		}
+	}
+
+	for i, child := range block.Children {
+		for _, sibling := range block.Children[:i] {
+			if child.Network != nil && sibling.Network != nil && core.Overlaps(child.Network, sibling.Network) {
+				*problems = append(*problems, Problem{
+					Line:    child.Line,
+					Path:    joinPath(path, child.displayName()),
+					Message: fmt.Sprintf("%s overlaps with sibling %s (%s)", core.FormatNetwork(child.Network), sibling.displayName(), core.FormatNetwork(sibling.Network)),
+				})
+			}
+		}
+	}
+	for _, child := range block.Children {
+		validate(child, block, path, problems)
+	}
+}
+
+// checkSize checks that the block has the size it declares, as a prefix length or a number of addresses.
+func checkSize(block *Block) error {
+	ones, _ := block.Network.Mask.Size()
+	if prefixLength, ok := strings.CutPrefix(block.Size, "/"); ok {
+		expected, err := strconv.Atoi(prefixLength)
+		if err != nil {
+			return fmt.Errorf("invalid size: %s", block.Size)
+		}
+		if ones != expected {
+			return fmt.Errorf("%s is /%d, but its declared size is /%d", core.FormatNetwork(block.Network), ones, expected)
+		}
+		return nil
+	}
+
+	expected, ok := new(big.Int).SetString(block.Size, 10)
+	if !ok {
+		return fmt.Errorf("invalid size: %s", block.Size)
+	}
+	if count := core.GetAddressCount(block.Network); count.Cmp(expected) != 0 {
+		return fmt.Errorf("%s has %s addresses, but its declared size is %s", core.FormatNetwork(block.Network), count, expected)
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+// displayName returns the name of the block, or a placeholder for blocks without a name.
+func (b *Block) displayName() string {
+	if b.Name == "" {
+		return "(unnamed)"
+	}
+	return b.Name
+}
@@ -0,0 +1,20 @@
+package render
+
+// HilbertPoint returns the coordinates of the d-th point on a Hilbert curve that fills a square
+// of 2^order by 2^order points. The curve starts at (0, 0) and ends at (2^order-1, 0).
+func HilbertPoint(order int, d uint64) (x, y int) {
+	for s := 1; s < 1<<order; s <<= 1 {
+		rx := int(d/2) & 1
+		ry := int(d^uint64(rx)) & 1
+		if ry == 0 {
+			if rx == 1 {
+				x, y = s-1-x, s-1-y
+			}
+			x, y = y, x
+		}
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+	return x, y
+}
@@ -0,0 +1,43 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHilbertPoint(t *testing.T) {
+	// The first order curve visits the corners of a square.
+	expected := [][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	for d, point := range expected {
+		x, y := render.HilbertPoint(1, uint64(d))
+		assert.Equal(t, point, [2]int{x, y}, "Point %d is not correct", d)
+	}
+}
+
+func TestHilbertPointVisitsEveryPointOnce(t *testing.T) {
+	const order = 4
+	const side = 1 << order
+
+	seen := make(map[[2]int]bool)
+	previousX, previousY := render.HilbertPoint(order, 0)
+	for d := uint64(0); d < side*side; d++ {
+		x, y := render.HilbertPoint(order, d)
+		assert.False(t, seen[[2]int{x, y}], "Point (%d, %d) is visited twice", x, y)
+		seen[[2]int{x, y}] = true
+
+		// Consecutive points on the curve are always neighbours.
+		distance := abs(x-previousX) + abs(y-previousY)
+		assert.LessOrEqual(t, distance, 1, "Point %d is not a neighbour of the previous point", d)
+		previousX, previousY = x, y
+	}
+	assert.Len(t, seen, side*side)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
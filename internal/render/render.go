@@ -0,0 +1,244 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+const (
+	// MaxOrder limits a map to 4096 by 4096 pixels.
+	MaxOrder = 12
+	// MaxImageSize limits a scaled up map to 16384 by 16384 image pixels.
+	MaxImageSize = 16384
+)
+
+var (
+	freeColor       = color.RGBA{0xee, 0xee, 0xee, 0xff}
+	allocatedColor  = color.RGBA{0x2e, 0x7d, 0x32, 0xff}
+	partialColor    = color.RGBA{0xa5, 0xd6, 0xa7, 0xff}
+	textColor       = color.RGBA{0x21, 0x21, 0x21, 0xff}
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	// labelColors is a palette of colours that are easy to tell apart.
+	labelColors = []color.RGBA{
+		{0x1f, 0x77, 0xb4, 0xff},
+		{0xff, 0x7f, 0x0e, 0xff},
+		{0x2c, 0xa0, 0x2c, 0xff},
+		{0xd6, 0x27, 0x28, 0xff},
+		{0x94, 0x67, 0xbd, 0xff},
+		{0x8c, 0x56, 0x4b, 0xff},
+		{0xe3, 0x77, 0xc2, 0xff},
+		{0x7f, 0x7f, 0x7f, 0xff},
+		{0xbc, 0xbd, 0x22, 0xff},
+		{0x17, 0xbe, 0xcf, 0xff},
+	}
+
+	// The colours of the lowest and highest counts.
+	lowCountColor  = color.RGBA{0xff, 0xf5, 0xb0, 0xff}
+	highCountColor = color.RGBA{0xb0, 0x00, 0x00, 0xff}
+)
+
+// Allocation is a network with the label it is coloured by.
+type Allocation struct {
+	Network *net.IPNet
+	Label   string
+}
+
+// LegendEntry explains what a colour on the map stands for.
+type LegendEntry struct {
+	Color color.RGBA
+	Label string
+}
+
+// Map lays out the addresses of a network on a Hilbert curve, one pixel per subnet with a prefix length of
+// PixelPrefixLength. Addresses that are close to each other end up close to each other on the map.
+type Map struct {
+	Network           *net.IPNet
+	PixelPrefixLength int
+	Legend            []LegendEntry
+
+	order  int
+	pixels []color.RGBA
+}
+
+// NewMap returns an empty map of the network. The difference between the prefix length of the network and
+// the pixel prefix length must be even, so that the pixels fill a square.
+func NewMap(network *net.IPNet, pixelPrefixLength int) (*Map, error) {
+	ones, bits := network.Mask.Size()
+	depth := pixelPrefixLength - ones
+	if depth < 0 || pixelPrefixLength > bits {
+		return nil, fmt.Errorf("pixel prefix length must be between /%d and /%d", ones, bits)
+	}
+	if depth%2 != 0 {
+		return nil, fmt.Errorf("pixel prefix length must differ an even number of bits from /%d", ones)
+	}
+	if depth/2 > MaxOrder {
+		return nil, fmt.Errorf("pixel prefix length must be at most /%d", ones+2*MaxOrder)
+	}
+
+	m := &Map{
+		Network:           network,
+		PixelPrefixLength: pixelPrefixLength,
+		order:             depth / 2,
+		pixels:            make([]color.RGBA, 1<<depth),
+	}
+	for i := range m.pixels {
+		m.pixels[i] = freeColor
+	}
+	return m, nil
+}
+
+// Side returns the width and height of the map in pixels.
+func (m *Map) Side() int {
+	return 1 << m.order
+}
+
+// MaxScale returns the largest scale that the map can be drawn at without exceeding MaxImageSize.
+func (m *Map) MaxScale() int {
+	return MaxImageSize / m.Side()
+}
+
+// Image returns the map as an image of Side by Side pixels.
+func (m *Map) Image() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, m.Side(), m.Side()))
+	for d, c := range m.pixels {
+		x, y := HilbertPoint(m.order, uint64(d))
+		img.SetRGBA(x, y, c)
+	}
+	return img
+}
+
+// pixelRange returns the first and last pixel that the network covers, and whether it covers them completely.
+func (m *Map) pixelRange(network *net.IPNet) (first, last int, full bool, err error) {
+	if !core.ContainsNetwork(m.Network, network) {
+		return 0, 0, false, fmt.Errorf("%s is %w %s", core.FormatNetwork(network), core.ErrOutsideNetwork, core.FormatNetwork(m.Network))
+	}
+	ones, bits := network.Mask.Size()
+	base := core.GetNetworkRange(m.Network).First
+	r := core.GetNetworkRange(network)
+	return m.pixel(base, r.First, bits), m.pixel(base, r.Last, bits), ones <= m.PixelPrefixLength, nil
+}
+
+// pixel returns the pixel that the address falls in.
+func (m *Map) pixel(base, ip net.IP, bits int) int {
+	offset := new(big.Int).Sub(new(big.Int).SetBytes(ip), new(big.Int).SetBytes(base))
+	return int(offset.Rsh(offset, uint(bits-m.PixelPrefixLength)).Int64())
+}
+
+// ColorByAllocation colours the pixels that are fully or partially covered by any of the allocations.
+func (m *Map) ColorByAllocation(allocations []*net.IPNet) error {
+	for _, allocation := range allocations {
+		first, last, full, err := m.pixelRange(allocation)
+		if err != nil {
+			return err
+		}
+		for i := first; i <= last; i++ {
+			if full {
+				m.pixels[i] = allocatedColor
+			} else if m.pixels[i] != allocatedColor {
+				m.pixels[i] = partialColor
+			}
+		}
+	}
+	m.Legend = []LegendEntry{
+		{Color: allocatedColor, Label: "allocated"},
+		{Color: partialColor, Label: "partially allocated"},
+		{Color: freeColor, Label: "free"},
+	}
+	return nil
+}
+
+// ColorByLabel colours the pixels by the label of the allocation that covers them. Pixels that are only
+// partially covered get a lighter colour.
+func (m *Map) ColorByLabel(allocations []Allocation) error {
+	colors := make(map[string]color.RGBA)
+	full := make([]bool, len(m.pixels))
+	m.Legend = nil
+	for _, allocation := range allocations {
+		label := allocation.Label
+		if label == "" {
+			label = core.FormatNetwork(allocation.Network)
+		}
+		c, ok := colors[label]
+		if !ok {
+			c = labelColors[len(colors)%len(labelColors)]
+			colors[label] = c
+			m.Legend = append(m.Legend, LegendEntry{Color: c, Label: label})
+		}
+
+		first, last, isFull, err := m.pixelRange(allocation.Network)
+		if err != nil {
+			return err
+		}
+		for i := first; i <= last; i++ {
+			if isFull {
+				m.pixels[i], full[i] = c, true
+			} else if !full[i] {
+				m.pixels[i] = blend(c, backgroundColor, 0.5)
+			}
+		}
+	}
+	m.Legend = append(m.Legend, LegendEntry{Color: freeColor, Label: "free"})
+	return nil
+}
+
+// ColorByCount colours the pixels by the number of the given addresses that fall in them, on a logarithmic scale.
+// Addresses outside of the network are ignored.
+func (m *Map) ColorByCount(addresses []net.IP) {
+	_, bits := m.Network.Mask.Size()
+	base := core.GetNetworkRange(m.Network).First
+	counts := make([]uint64, len(m.pixels))
+	var highest uint64
+	for _, ip := range addresses {
+		if !core.ContainsAddress(m.Network, ip) {
+			continue
+		}
+		i := m.pixel(base, ip, bits)
+		counts[i]++
+		highest = max(highest, counts[i])
+	}
+
+	scale := math.Log1p(float64(highest))
+	for i, count := range counts {
+		if count > 0 {
+			m.pixels[i] = blend(highCountColor, lowCountColor, math.Log1p(float64(count))/scale)
+		}
+	}
+
+	m.Legend = nil
+	if highest > 0 {
+		previous := uint64(0)
+		for _, step := range []float64{0, 0.25, 0.5, 0.75, 1} {
+			count := max(1, uint64(math.Round(math.Expm1(step*scale))))
+			if count == previous {
+				continue
+			}
+			m.Legend = append(m.Legend, LegendEntry{
+				Color: blend(highCountColor, lowCountColor, math.Log1p(float64(count))/scale),
+				Label: strconv.FormatUint(count, 10),
+			})
+			previous = count
+		}
+	}
+	m.Legend = append(m.Legend, LegendEntry{Color: freeColor, Label: "0"})
+}
+
+// Title describes what the map shows.
+func (m *Map) Title() string {
+	return fmt.Sprintf("%s, 1 pixel = /%d", core.FormatNetwork(m.Network), m.PixelPrefixLength)
+}
+
+// blend mixes the colours a and b, with the given weight of a.
+func blend(a, b color.RGBA, weight float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x)*weight + float64(y)*(1-weight)))
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
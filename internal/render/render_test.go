@@ -0,0 +1,141 @@
+package render_test
+
+import (
+	"bytes"
+	"image/png"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	network, err := core.ParseCIDR(cidr)
+	assert.NoError(t, err)
+	return network
+}
+
+func TestNewMap(t *testing.T) {
+	tests := []struct {
+		name              string
+		cidr              string
+		pixelPrefixLength int
+		expectedSide      int
+		wantErr           bool
+	}{
+		{
+			name:              "Map an IPv4 /8 with /24 pixels",
+			cidr:              "10.0.0.0/8",
+			pixelPrefixLength: 24,
+			expectedSide:      256,
+		},
+		{
+			name:              "Map an IPv6 /32 with /48 pixels",
+			cidr:              "2001:db8::/32",
+			pixelPrefixLength: 48,
+			expectedSide:      256,
+		},
+		{
+			name:              "Pixels cannot be larger than the network",
+			cidr:              "10.0.0.0/16",
+			pixelPrefixLength: 8,
+			wantErr:           true,
+		},
+		{
+			name:              "Pixels must fill a square",
+			cidr:              "10.0.0.0/16",
+			pixelPrefixLength: 23,
+			wantErr:           true,
+		},
+		{
+			name:              "Maps cannot be larger than 4096 by 4096 pixels",
+			cidr:              "0.0.0.0/0",
+			pixelPrefixLength: 32,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := render.NewMap(mustParseCIDR(t, tt.cidr), tt.pixelPrefixLength)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSide, m.Side(), "Side of the map is not correct")
+		})
+	}
+}
+
+func TestColorByLabel(t *testing.T) {
+	m, err := render.NewMap(mustParseCIDR(t, "10.0.0.0/16"), 18)
+	assert.NoError(t, err)
+
+	err = m.ColorByLabel([]render.Allocation{
+		{Network: mustParseCIDR(t, "10.0.0.0/18"), Label: "web"},
+		{Network: mustParseCIDR(t, "10.0.192.0/24"), Label: "db"},
+	})
+	assert.NoError(t, err)
+
+	// The curve visits the quadrants top left, bottom left, bottom right and top right.
+	img := m.Image()
+	web, db, free := m.Legend[0], m.Legend[1], m.Legend[2]
+	assert.Equal(t, "web", web.Label)
+	assert.Equal(t, web.Color, img.RGBAAt(0, 0), "First quadrant should be allocated to web")
+	assert.Equal(t, free.Color, img.RGBAAt(0, 1), "Second quadrant should be free")
+	assert.NotEqual(t, db.Color, img.RGBAAt(1, 0), "Partially allocated quadrant should have a lighter colour")
+	assert.NotEqual(t, free.Color, img.RGBAAt(1, 0), "Partially allocated quadrant should not be free")
+
+	err = m.ColorByLabel([]render.Allocation{{Network: mustParseCIDR(t, "10.1.0.0/24")}})
+	assert.ErrorIs(t, err, core.ErrOutsideNetwork)
+}
+
+func TestColorByCount(t *testing.T) {
+	m, err := render.NewMap(mustParseCIDR(t, "10.0.0.0/16"), 18)
+	assert.NoError(t, err)
+
+	m.ColorByCount([]net.IP{
+		net.ParseIP("10.0.0.1").To4(),
+		net.ParseIP("10.0.0.2").To4(),
+		net.ParseIP("10.0.64.1").To4(),
+		net.ParseIP("192.168.0.1").To4(),
+	})
+	img := m.Image()
+	assert.NotEqual(t, img.RGBAAt(0, 0), img.RGBAAt(0, 1), "Pixels with different counts should have different colours")
+	assert.Equal(t, "2", m.Legend[len(m.Legend)-2].Label, "Legend should end with the highest count")
+}
+
+func TestWritePNGAndSVG(t *testing.T) {
+	m, err := render.NewMap(mustParseCIDR(t, "2001:db8::/32"), 40)
+	assert.NoError(t, err)
+	assert.NoError(t, m.ColorByAllocation([]*net.IPNet{mustParseCIDR(t, "2001:db8::/36")}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, render.WritePNG(&buf, m, 4))
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, img.Bounds().Dx(), 16*4)
+
+	buf.Reset()
+	assert.NoError(t, render.WriteSVG(&buf, m, 4))
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg"), "SVG should start with an svg element")
+	assert.Contains(t, svg, "partially allocated")
+	assert.Contains(t, svg, "2001:db8::/32, 1 pixel = /40")
+}
+
+func TestWriteScale(t *testing.T) {
+	m, err := render.NewMap(mustParseCIDR(t, "10.0.0.0/16"), 24)
+	assert.NoError(t, err)
+	assert.Equal(t, render.MaxImageSize/16, m.MaxScale())
+
+	var buf bytes.Buffer
+	assert.Error(t, render.WritePNG(&buf, m, m.MaxScale()+1), "Scale above the maximum should be rejected")
+	assert.Error(t, render.WriteSVG(&buf, m, 100000), "Scale above the maximum should be rejected")
+	assert.Error(t, render.WritePNG(&buf, m, 0), "Scale below 1 should be rejected")
+	assert.Zero(t, buf.Len(), "Nothing should be written for a rejected scale")
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	margin = 16
+	// fontScale is the size of a font pixel in image pixels.
+	fontScale = 2
+	// lineHeight is the height of a legend line in image pixels.
+	lineHeight = (glyphHeight + 4) * fontScale
+	// swatchGap is the space between a colour swatch and its label.
+	swatchGap = 8
+)
+
+// legendSize returns the width and height of the legend, including the title.
+func legendSize(m *Map) (width, height int) {
+	width = textWidth(m.Title(), fontScale)
+	for _, entry := range m.Legend {
+		width = max(width, glyphHeight*fontScale+swatchGap+textWidth(entry.Label, fontScale))
+	}
+	return width, (len(m.Legend) + 1) * lineHeight
+}
+
+// checkScale returns an error if the map cannot be drawn at the given scale.
+func checkScale(m *Map, scale int) error {
+	if scale < 1 || scale > m.MaxScale() {
+		return fmt.Errorf("scale must be between 1 and %d for a map of %d by %d pixels", m.MaxScale(), m.Side(), m.Side())
+	}
+	return nil
+}
+
+// WritePNG writes the map as a PNG image, every map pixel is drawn as scale by scale image pixels.
+// The legend is drawn below the map.
+func WritePNG(w io.Writer, m *Map, scale int) error {
+	if err := checkScale(m, scale); err != nil {
+		return err
+	}
+	side := m.Side() * scale
+	legendWidth, legendHeight := legendSize(m)
+	img := image.NewRGBA(image.Rect(0, 0, max(side, legendWidth)+2*margin, side+legendHeight+3*margin))
+	fillRect(img, 0, 0, img.Bounds().Dx(), img.Bounds().Dy(), backgroundColor)
+
+	mapImage := m.Image()
+	for y := 0; y < m.Side(); y++ {
+		for x := 0; x < m.Side(); x++ {
+			fillRect(img, margin+x*scale, margin+y*scale, scale, scale, mapImage.RGBAAt(x, y))
+		}
+	}
+
+	y := side + 2*margin
+	drawText(img, margin, y, m.Title(), fontScale, textColor)
+	for _, entry := range m.Legend {
+		y += lineHeight
+		swatch := glyphHeight * fontScale
+		fillRect(img, margin, y, swatch, swatch, entry.Color)
+		drawText(img, margin+swatch+swatchGap, y, entry.Label, fontScale, textColor)
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSVG writes the map as an SVG image, every map pixel is drawn as a square of scale by scale units.
+// Horizontal runs of pixels with the same colour are drawn as a single rectangle. The legend is drawn below the map.
+func WriteSVG(w io.Writer, m *Map, scale int) error {
+	if err := checkScale(m, scale); err != nil {
+		return err
+	}
+	side := m.Side() * scale
+	legendWidth, legendHeight := legendSize(m)
+	width, height := max(side, legendWidth)+2*margin, side+legendHeight+3*margin
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, `<title>%s</title>`+"\n", html.EscapeString(m.Title()))
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, hexColor(backgroundColor))
+
+	mapImage := m.Image()
+	for y := 0; y < m.Side(); y++ {
+		for x := 0; x < m.Side(); {
+			c := mapImage.RGBAAt(x, y)
+			run := 1
+			for x+run < m.Side() && mapImage.RGBAAt(x+run, y) == c {
+				run++
+			}
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				margin+x*scale, margin+y*scale, run*scale, scale, hexColor(c))
+			x += run
+		}
+	}
+
+	fontSize := glyphHeight * fontScale
+	y := side + 2*margin
+	fmt.Fprintf(bw, `<g font-family="monospace" font-size="%d" fill="%s">`+"\n", fontSize, hexColor(textColor))
+	fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`+"\n", margin, y+fontSize, html.EscapeString(m.Title()))
+	for _, entry := range m.Legend {
+		y += lineHeight
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", margin, y, fontSize, fontSize, hexColor(entry.Color))
+		fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`+"\n", margin+fontSize+swatchGap, y+fontSize, html.EscapeString(entry.Label))
+	}
+	fmt.Fprintln(bw, `</g>`)
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// hexColor formats the colour as #rrggbb.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,198 @@
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+)
+
+// DefaultPatterns match the Terraform, Kubernetes, Helm, Ansible and JSON files that are scanned
+// by default.
+var DefaultPatterns = []string{"*.tf", "*.tfvars", "*.tfstate", "*.hcl", "*.yaml", "*.yml", "*.json"}
+
+// skippedDirectories are never scanned, they hold version control data, caches or vendored code.
+var skippedDirectories = map[string]bool{
+	".git":              true,
+	".terraform":        true,
+	".terragrunt-cache": true,
+	"node_modules":      true,
+}
+
+var (
+	// ipv4Pattern matches dotted quads, optionally followed by a prefix length. Invalid addresses such
+	// as 10.0.0.300 are matched as well, so that they can be reported.
+	ipv4Pattern = regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}(?:/\d{1,3})?`)
+	// ipv6Pattern matches candidates for IPv6 addresses, optionally followed by a prefix length. Only
+	// candidates that parse as an IPv6 address are kept.
+	ipv6Pattern = regexp.MustCompile(`[0-9A-Fa-f:]*:[0-9A-Fa-f]*:[0-9A-Fa-f:.]*(?:/\d{1,3})?`)
+)
+
+// Walk scans the files below root whose name matches one of the patterns, and returns every prefix
+// and address literal that was found. Root may also be a single file, which is always scanned.
+func Walk(root string, patterns []string) ([]input.Line, error) {
+	var lines []input.Line
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skippedDirectories[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path != root && !matches(d.Name(), patterns) {
+			return nil
+		}
+		fileLines, err := ExtractFile(path)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fileLines...)
+		return nil
+	})
+	return lines, err
+}
+
+func matches(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractFile returns every prefix and address literal in the named file.
+func ExtractFile(name string) ([]input.Line, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Extract(f, name)
+}
+
+// Extract returns every prefix and address literal in r, in the order they occur in.
+func Extract(r io.Reader, source string) ([]input.Line, error) {
+	var lines []input.Line
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		for _, literal := range extractLine(scanner.Text()) {
+			lines = append(lines, input.Line{Source: source, Number: number, Column: literal.start + 1, Field: literal.text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return lines, nil
+}
+
+type literal struct {
+	text  string
+	start int
+}
+
+// extractLine finds the literals in a single line. IPv6 literals are found first, so that an
+// IPv4-mapped address is not reported a second time as an IPv4 address.
+func extractLine(text string) []literal {
+	var literals []literal
+	var taken [][]int
+	for _, loc := range ipv6Pattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		// Trailing colons and dots belong to the surrounding text, as in "[::1]:" or "2001:db8::1.".
+		for end > start && strings.ContainsRune(":.", rune(text[end-1])) && !strings.HasSuffix(text[start:end], "::") {
+			end--
+		}
+		value := text[start:end]
+		address, _, _ := strings.Cut(value, "/")
+		if !isBoundary(text, start, end) || strings.Count(address, ":") < 2 || net.ParseIP(address) == nil {
+			continue
+		}
+		literals = append(literals, literal{text: value, start: start})
+		taken = append(taken, []int{start, end})
+	}
+
+	for _, loc := range ipv4Pattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if !isBoundary(text, start, end) || within(taken, start) {
+			continue
+		}
+		literals = append(literals, literal{text: text[start:end], start: start})
+	}
+
+	sort.Slice(literals, func(i, j int) bool {
+		return literals[i].start < literals[j].start
+	})
+	return literals
+}
+
+// isBoundary reports whether text[start:end] is not part of a longer word, version number or
+// dotted identifier.
+func isBoundary(text string, start, end int) bool {
+	if start > 0 {
+		if c := text[start-1]; isWordByte(c) || c == '.' || c == ':' {
+			return false
+		}
+	}
+	if end < len(text) {
+		c := text[end]
+		if isWordByte(c) || c == '/' {
+			return false
+		}
+		if c == '.' && end+1 < len(text) && isWordByte(text[end+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func within(spans [][]int, offset int) bool {
+	for _, span := range spans {
+		if offset >= span[0] && offset < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultConfig returns the lint config that is used for scans. The same prefix is usually
+// referenced from many places, so duplicates are not reported and only overlaps between distinct
+// CIDR ranges are. Those are often intended, such as a subnet inside its VPC, so they are reported
+// as warning rather than error, and the default routes that security groups and route tables are
+// full of are left out.
+// Private, loopback, link-local (such as cloud metadata endpoints) and unspecified addresses are
+// common in configuration and allowed.
+func DefaultConfig() *lint.Config {
+	off := lint.SeverityOff
+	warning := lint.SeverityWarning
+	ignore := true
+	return &lint.Config{Rules: map[string]lint.RuleConfig{
+		lint.RuleDuplicate.ID: {Severity: &off},
+		lint.RuleOverlap.ID:   {Severity: &warning, IgnoreAddresses: &ignore, IgnoreDefaultRoutes: &ignore},
+		lint.RuleSpecialPurpose.ID: {Allow: []string{
+			core.SpecialPurposePrivate,
+			core.SpecialPurposeLoopback,
+			core.SpecialPurposeLinkLocal,
+			core.SpecialPurposeUnspecified,
+		}},
+	}}
+}
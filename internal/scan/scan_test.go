@@ -0,0 +1,160 @@
+package scan_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+	"github.com/bschaatsbergen/cidr/internal/scan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []input.Line
+	}{
+		{
+			name: "Terraform",
+			text: "resource \"aws_vpc\" \"main\" {\n  cidr_block = \"10.0.0.0/16\"\n}\n",
+			expected: []input.Line{
+				{Source: "main.tf", Number: 2, Column: 17, Field: "10.0.0.0/16"},
+			},
+		},
+		{
+			name: "Terraform list",
+			text: `cidr_blocks = ["10.0.1.0/24", "10.0.2.0/24"]`,
+			expected: []input.Line{
+				{Source: "main.tf", Number: 1, Column: 17, Field: "10.0.1.0/24"},
+				{Source: "main.tf", Number: 1, Column: 32, Field: "10.0.2.0/24"},
+			},
+		},
+		{
+			name: "YAML",
+			text: "spec:\n  podCIDR: 10.244.0.0/16\n  - cidr: 2001:db8::/64\n",
+			expected: []input.Line{
+				{Source: "main.tf", Number: 2, Column: 12, Field: "10.244.0.0/16"},
+				{Source: "main.tf", Number: 3, Column: 11, Field: "2001:db8::/64"},
+			},
+		},
+		{
+			name: "JSON",
+			text: `{"subnets":["192.168.1.0/24"],"dns":"8.8.8.8"}`,
+			expected: []input.Line{
+				{Source: "main.tf", Number: 1, Column: 14, Field: "192.168.1.0/24"},
+				{Source: "main.tf", Number: 1, Column: 38, Field: "8.8.8.8"},
+			},
+		},
+		{
+			name: "Addresses with ports",
+			text: "endpoint: 10.0.0.1:8080\nurl: http://[2001:db8::1]:443/",
+			expected: []input.Line{
+				{Source: "main.tf", Number: 1, Column: 11, Field: "10.0.0.1"},
+				{Source: "main.tf", Number: 2, Column: 14, Field: "2001:db8::1"},
+			},
+		},
+		{
+			name: "IPv4-mapped IPv6 address",
+			text: "address: ::ffff:10.0.0.1",
+			expected: []input.Line{
+				{Source: "main.tf", Number: 1, Column: 10, Field: "::ffff:10.0.0.1"},
+			},
+		},
+		{
+			name: "Invalid IPv4 address",
+			text: "cidr: 10.0.0.300/24",
+			expected: []input.Line{
+				{Source: "main.tf", Number: 1, Column: 7, Field: "10.0.0.300/24"},
+			},
+		},
+		{
+			name: "Versions, timestamps and hashes",
+			text: "version: 1.2.3.4.5\nimage: nginx:1.25.3\ntime: 12:30:00\nid: deadbeef::cafe\nsha: a1b2:c3d4\nv1.2.3.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := scan.Extract(strings.NewReader(tt.text), "main.tf")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.tf":                    `cidr_block = "10.0.0.0/16"`,
+		"values.yaml":                "cidr: 10.0.1.0/24",
+		"README.md":                  "10.1.0.0/16",
+		".terraform/modules/vpc.tf":  `cidr_block = "10.2.0.0/16"`,
+		"environments/prod.tfvars":   `vpc_cidr = "10.3.0.0/16"`,
+		"node_modules/pkg/data.json": `["10.4.0.0/16"]`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	lines, err := scan.Walk(root, scan.DefaultPatterns)
+	require.NoError(t, err)
+	var fields []string
+	for _, line := range lines {
+		fields = append(fields, line.Field)
+	}
+	assert.Equal(t, []string{"10.3.0.0/16", "10.0.0.0/16", "10.0.1.0/24"}, fields)
+
+	lines, err = scan.Walk(filepath.Join(root, "README.md"), scan.DefaultPatterns)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, filepath.Join(root, "README.md"), lines[0].Source)
+}
+
+func TestDefaultConfig(t *testing.T) {
+	text := "vpc: 10.0.0.0/16\n" +
+		"subnet: 10.0.1.0/24\n" +
+		"subnet: 10.0.1.0/24\n" +
+		"gateway: 10.0.1.1\n" +
+		"metadata: 169.254.169.254\n" +
+		"docs: 192.0.2.0/24\n" +
+		"typo: 10.0.2.1/24\n"
+	lines, err := scan.Extract(strings.NewReader(text), "values.yaml")
+	require.NoError(t, err)
+
+	var rules []string
+	for _, finding := range lint.Lint(lines, scan.DefaultConfig()) {
+		rules = append(rules, finding.Rule)
+	}
+	assert.ElementsMatch(t, []string{"overlap", "special-purpose", "host-bits-set", "overlap"}, rules)
+}
+
+func TestDefaultConfigDefaultRoutes(t *testing.T) {
+	text := "resource \"aws_vpc\" \"main\" {\n" +
+		"  cidr_block = \"10.0.0.0/16\"\n" +
+		"}\n" +
+		"resource \"aws_subnet\" \"a\" {\n" +
+		"  cidr_block = \"10.0.1.0/24\"\n" +
+		"}\n" +
+		"resource \"aws_security_group_rule\" \"egress\" {\n" +
+		"  cidr_blocks      = [\"0.0.0.0/0\"]\n" +
+		"  ipv6_cidr_blocks = [\"::/0\"]\n" +
+		"}\n" +
+		"resource \"aws_route\" \"internet\" {\n" +
+		"  destination_cidr_block = \"0.0.0.0/0\"\n" +
+		"}\n"
+	lines, err := scan.Extract(strings.NewReader(text), "main.tf")
+	require.NoError(t, err)
+
+	findings := lint.Lint(lines, scan.DefaultConfig())
+	require.Len(t, findings, 1, "Only the subnet inside its VPC should be reported")
+	assert.Equal(t, lint.RuleOverlap.ID, findings[0].RuleID)
+	assert.Equal(t, lint.SeverityWarning, findings[0].Severity)
+	assert.Equal(t, 5, findings[0].Line)
+}
@@ -0,0 +1,412 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/input"
+	"github.com/bschaatsbergen/cidr/internal/lint"
+)
+
+type networkRequest struct {
+	Network string `json:"network"`
+}
+
+type explainResponse struct {
+	Network            string `json:"network"`
+	Version            int    `json:"version"`
+	BaseAddress        string `json:"baseAddress"`
+	Netmask            string `json:"netmask"`
+	PrefixLength       int    `json:"prefixLength"`
+	Addresses          string `json:"addresses"`
+	HostAddresses      string `json:"hostAddresses"`
+	FirstUsableAddress string `json:"firstUsableAddress,omitempty"`
+	LastUsableAddress  string `json:"lastUsableAddress,omitempty"`
+	BroadcastAddress   string `json:"broadcastAddress,omitempty"`
+}
+
+func (s *server) explain(_ *http.Request, req *networkRequest) (*explainResponse, error) {
+	network, err := s.parseNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	ones, bits := network.Mask.Size()
+	resp := &explainResponse{
+		Network:       core.FormatNetwork(network),
+		Version:       6,
+		BaseAddress:   core.FormatAddress(core.GetBaseAddress(network)),
+		Netmask:       core.NetMaskToIPAddress(core.GetNetmask(network)).String(),
+		PrefixLength:  ones,
+		Addresses:     core.GetAddressCount(network).String(),
+		HostAddresses: core.GetHostAddressCount(network).String(),
+	}
+	if bits == 8*net.IPv4len {
+		resp.Version = 4
+	}
+	if first, err := core.GetFirstUsableIPAddress(network); err == nil {
+		resp.FirstUsableAddress = core.FormatAddress(first)
+	}
+	if last, err := core.GetLastUsableIPAddress(network); err == nil {
+		resp.LastUsableAddress = core.FormatAddress(last)
+	}
+	if broadcast, err := core.GetBroadcastAddress(network); err == nil {
+		resp.BroadcastAddress = core.FormatAddress(broadcast)
+	}
+	return resp, nil
+}
+
+type countResponse struct {
+	Network   string `json:"network"`
+	Addresses string `json:"addresses"`
+}
+
+func (s *server) count(_ *http.Request, req *networkRequest) (*countResponse, error) {
+	network, err := s.parseNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	return &countResponse{Network: core.FormatNetwork(network), Addresses: core.GetAddressCount(network).String()}, nil
+}
+
+type containsRequest struct {
+	Network string   `json:"network"`
+	Needles []string `json:"needles"`
+}
+
+type containsResult struct {
+	Needle string `json:"needle"`
+	// Containment is one of full, partial or none.
+	Containment string `json:"containment"`
+}
+
+type containsResponse struct {
+	Results []containsResult `json:"results"`
+	// All is true when every needle is fully contained.
+	All bool `json:"all"`
+	// Any is true when at least one needle is fully contained.
+	Any bool `json:"any"`
+}
+
+var containmentNames = map[core.Containment]string{
+	core.FullyContained:     "full",
+	core.PartiallyContained: "partial",
+	core.NotContained:       "none",
+}
+
+func (s *server) contains(_ *http.Request, req *containsRequest) (*containsResponse, error) {
+	network, err := s.parseNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Needles) == 0 {
+		return nil, badRequest("provide at least 1 needle")
+	}
+	needles, err := s.parsePrefixes(req.Needles)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &containsResponse{Results: []containsResult{}, All: true}
+	for i, needle := range needles {
+		containment := core.GetContainment(network, needle)
+		resp.Results = append(resp.Results, containsResult{Needle: req.Needles[i], Containment: containmentNames[containment]})
+		resp.All = resp.All && containment == core.FullyContained
+		resp.Any = resp.Any || containment == core.FullyContained
+	}
+	return resp, nil
+}
+
+type networksRequest struct {
+	Networks []string `json:"networks"`
+}
+
+type overlapsResponse struct {
+	Overlaps bool `json:"overlaps"`
+	// Pairs lists every pair of networks that overlap, in the order of the request.
+	Pairs [][2]string `json:"pairs"`
+}
+
+func (s *server) overlaps(_ *http.Request, req *networksRequest) (*overlapsResponse, error) {
+	if len(req.Networks) < 2 {
+		return nil, badRequest("provide at least 2 networks")
+	}
+	networks, err := s.parseNetworks(req.Networks)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := s.overlappingPairs(networks)
+	if err != nil {
+		return nil, err
+	}
+	resp := &overlapsResponse{Pairs: make([][2]string, len(pairs))}
+	for k, pair := range pairs {
+		resp.Pairs[k] = [2]string{req.Networks[pair[0]], req.Networks[pair[1]]}
+	}
+	resp.Overlaps = len(resp.Pairs) > 0
+	return resp, nil
+}
+
+// overlappingPairs returns the indexes of every pair of overlapping networks, in the order of the networks.
+// CIDR ranges either nest or are disjoint, so a sweep over the sorted ranges with a stack of the ranges
+// that are still open finds every pair in time proportional to the number of networks and pairs.
+func (s *server) overlappingPairs(networks []*net.IPNet) ([][2]int, error) {
+	ranges := make([]core.IPRange, len(networks))
+	order := make([]int, len(networks))
+	for i, network := range networks {
+		ranges[i] = core.GetNetworkRange(network)
+		order[i] = i
+	}
+	// Sort by address family and first address, larger ranges before the ranges that they contain.
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := ranges[order[a]], ranges[order[b]]
+		if len(ra.First) != len(rb.First) {
+			return len(ra.First) < len(rb.First)
+		}
+		if c := bytes.Compare(ra.First, rb.First); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(ra.Last, rb.Last) > 0
+	})
+
+	var pairs [][2]int
+	var open []int
+	for _, i := range order {
+		r := ranges[i]
+		for len(open) > 0 {
+			top := ranges[open[len(open)-1]]
+			if len(top.Last) == len(r.First) && bytes.Compare(top.Last, r.First) >= 0 {
+				break
+			}
+			open = open[:len(open)-1]
+		}
+		for _, j := range open {
+			if 2*len(pairs) >= s.options.MaxNetworks {
+				return nil, s.tooManyNetworks()
+			}
+			pairs = append(pairs, [2]int{min(i, j), max(i, j)})
+		}
+		open = append(open, i)
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+	return pairs, nil
+}
+
+type divideRequest struct {
+	Network string `json:"network"`
+	Divisor int64  `json:"divisor"`
+}
+
+type networksResponse struct {
+	Networks []string `json:"networks"`
+}
+
+func (s *server) divide(_ *http.Request, req *divideRequest) (*networksResponse, error) {
+	network, err := s.parseNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	if req.Divisor > int64(s.options.MaxNetworks) {
+		return nil, s.tooManyNetworks()
+	}
+	subnets, err := core.DivideCIDR(network, req.Divisor)
+	if err != nil {
+		return nil, err
+	}
+	resp := &networksResponse{Networks: make([]string, len(subnets))}
+	for i, subnet := range subnets {
+		resp.Networks[i] = core.FormatNetwork(&subnet)
+	}
+	return resp, nil
+}
+
+type setRequest struct {
+	Lists [][]string `json:"lists"`
+}
+
+var setOperations = map[string]struct {
+	minLists  int
+	operation func(a, b *core.IPSet) *core.IPSet
+}{
+	"union":     {1, (*core.IPSet).Union},
+	"intersect": {2, (*core.IPSet).Intersection},
+	"diff":      {2, (*core.IPSet).Difference},
+	"xor":       {2, (*core.IPSet).SymmetricDifference},
+}
+
+func (s *server) setOperation(r *http.Request, req *setRequest) (*networksResponse, error) {
+	name := r.PathValue("operation")
+	op, ok := setOperations[name]
+	if !ok {
+		return nil, &apiError{status: http.StatusNotFound, code: codeNotFound, err: fmt.Errorf("unknown set operation: %s", name)}
+	}
+	if len(req.Lists) < op.minLists {
+		return nil, badRequest("provide at least %d lists", op.minLists)
+	}
+
+	var result *core.IPSet
+	for _, list := range req.Lists {
+		networks, err := s.parsePrefixes(list)
+		if err != nil {
+			return nil, err
+		}
+		set := core.NewIPSet(networks...)
+		if result == nil {
+			result = set
+		} else {
+			result = op.operation(result, set)
+		}
+	}
+
+	networks, err := s.networkStrings(result.Networks())
+	if err != nil {
+		return nil, err
+	}
+	return &networksResponse{Networks: networks}, nil
+}
+
+type utilizationRequest struct {
+	Network     string   `json:"network"`
+	Allocations []string `json:"allocations"`
+}
+
+type utilizationResponse struct {
+	Network              string   `json:"network"`
+	Addresses            string   `json:"addresses"`
+	Allocated            string   `json:"allocated"`
+	Free                 string   `json:"free"`
+	UsedPercentage       float64  `json:"usedPercentage"`
+	LargestFreeNetwork   string   `json:"largestFreeNetwork,omitempty"`
+	FragmentationPercent float64  `json:"fragmentationPercentage"`
+	FreeNetworks         []string `json:"freeNetworks"`
+}
+
+func (s *server) utilization(_ *http.Request, req *utilizationRequest) (*utilizationResponse, error) {
+	network, err := s.parseNetwork(req.Network)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := s.parsePrefixes(req.Allocations)
+	if err != nil {
+		return nil, err
+	}
+	utilization, err := core.GetUtilization(network, allocations)
+	if err != nil {
+		return nil, err
+	}
+	freeNetworks, err := s.networkStrings(utilization.FreeNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &utilizationResponse{
+		Network:              core.FormatNetwork(network),
+		Addresses:            utilization.Total.String(),
+		Allocated:            utilization.Allocated.String(),
+		Free:                 utilization.Free.String(),
+		UsedPercentage:       utilization.GetUsedPercentage(),
+		FragmentationPercent: utilization.GetFragmentation(),
+		FreeNetworks:         freeNetworks,
+	}
+	if largest := utilization.GetLargestFreeNetwork(); largest != nil {
+		resp.LargestFreeNetwork = core.FormatNetwork(largest)
+	}
+	return resp, nil
+}
+
+type validateResponse struct {
+	Valid    bool           `json:"valid"`
+	Findings []lint.Finding `json:"findings"`
+}
+
+// validate lints the networks with the default configuration. The line of a finding is the
+// 1-based index of the network in the request.
+func (s *server) validate(_ *http.Request, req *networksRequest) (*validateResponse, error) {
+	lines := make([]input.Line, len(req.Networks))
+	for i, network := range req.Networks {
+		lines[i] = input.Line{Source: "request", Number: i + 1, Column: 1, Field: network}
+	}
+	config := lint.DefaultConfig()
+	config.Unmap = s.options.Unmap
+	findings := lint.Lint(lines, config)
+
+	resp := &validateResponse{Valid: true, Findings: []lint.Finding{}}
+	for _, finding := range findings {
+		resp.Findings = append(resp.Findings, finding)
+		if finding.Severity == lint.SeverityError {
+			resp.Valid = false
+		}
+	}
+	return resp, nil
+}
+
+// tooManyNetworks reports a response that would list more networks than allowed.
+func (s *server) tooManyNetworks() error {
+	return &apiError{
+		status: http.StatusUnprocessableEntity,
+		code:   codeTooManyNetworks,
+		err:    fmt.Errorf("the response would hold more than %d networks", s.options.MaxNetworks),
+	}
+}
+
+func (s *server) networkStrings(networks []*net.IPNet) ([]string, error) {
+	if len(networks) > s.options.MaxNetworks {
+		return nil, s.tooManyNetworks()
+	}
+	values := make([]string, len(networks))
+	for i, network := range networks {
+		values[i] = core.FormatNetwork(network)
+	}
+	return values, nil
+}
+
+// parseNetwork parses a CIDR range, unmapping IPv4-mapped networks if the options say so.
+func (s *server) parseNetwork(value string) (*net.IPNet, error) {
+	network, err := core.ParseCIDR(value)
+	if err != nil {
+		return nil, err
+	}
+	return s.unmap(network), nil
+}
+
+func (s *server) parseNetworks(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, len(values))
+	for i, value := range values {
+		network, err := s.parseNetwork(value)
+		if err != nil {
+			return nil, err
+		}
+		networks[i] = network
+	}
+	return networks, nil
+}
+
+// parsePrefixes parses CIDR ranges, or single addresses which result in a network holding
+// only that address.
+func (s *server) parsePrefixes(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, len(values))
+	for i, value := range values {
+		network, err := input.ParsePrefix(value)
+		if err != nil {
+			return nil, err
+		}
+		networks[i] = s.unmap(network)
+	}
+	return networks, nil
+}
+
+func (s *server) unmap(network *net.IPNet) *net.IPNet {
+	if s.options.Unmap {
+		return core.Unmap(network)
+	}
+	return network
+}
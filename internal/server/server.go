@@ -0,0 +1,212 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+const (
+	// DefaultMaxRequestBytes is the default limit on the size of a request body.
+	DefaultMaxRequestBytes = 1 << 20
+	// DefaultMaxNetworks is the default limit on the number of networks in a response.
+	DefaultMaxNetworks = 1 << 16
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// Options configures the limits and address semantics of the API.
+type Options struct {
+	// MaxRequestBytes limits the size of a request body, larger requests are rejected.
+	MaxRequestBytes int64
+	// MaxNetworks limits the number of networks that a single response may list.
+	MaxNetworks int
+	// Unmap treats IPv4-mapped IPv6 networks and addresses as the IPv4 ones they stand for.
+	Unmap bool
+}
+
+// Error codes that are returned in the body of failed requests.
+const (
+	codeInvalidRequest        = "invalid_request"
+	codeRequestTooLarge       = "request_too_large"
+	codeUnsupportedMediaType  = "unsupported_media_type"
+	codeInvalidCIDR           = "invalid_cidr"
+	codeInvalidAddress        = "invalid_address"
+	codeInvalidDivisor        = "invalid_divisor"
+	codeInsufficientAddresses = "insufficient_address_space"
+	codeOutsideNetwork        = "outside_network"
+	codeTooManyNetworks       = "too_many_networks"
+	codeNotFound              = "not_found"
+	codeMethodNotAllowed      = "method_not_allowed"
+)
+
+// apiError is an error that is reported to the client with the given status and code.
+type apiError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, code: codeInvalidRequest, err: fmt.Errorf(format, args...)}
+}
+
+// coreErrors maps the errors of the core package to the status and code that are reported.
+var coreErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{core.ErrInvalidCIDR, http.StatusUnprocessableEntity, codeInvalidCIDR},
+	{core.ErrInvalidAddress, http.StatusUnprocessableEntity, codeInvalidAddress},
+	{core.ErrInvalidDivisor, http.StatusUnprocessableEntity, codeInvalidDivisor},
+	{core.ErrInsufficientAddressSpace, http.StatusUnprocessableEntity, codeInsufficientAddresses},
+	{core.ErrOutsideNetwork, http.StatusUnprocessableEntity, codeOutsideNetwork},
+}
+
+// errorResponse is the body of a failed request.
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type server struct {
+	options Options
+}
+
+// NewHandler returns the handler that serves the API and its OpenAPI document.
+func NewHandler(options Options) http.Handler {
+	if options.MaxRequestBytes <= 0 {
+		options.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if options.MaxNetworks <= 0 {
+		options.MaxNetworks = DefaultMaxNetworks
+	}
+	s := &server{options: options}
+
+	mux := http.NewServeMux()
+	mux.Handle("/openapi.json", allow(http.MethodGet, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPIDocument)
+	})))
+	mux.Handle("/healthz", allow(http.MethodGet, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})))
+	mux.Handle("/v1/explain", allow(http.MethodPost, handle(s, s.explain)))
+	mux.Handle("/v1/count", allow(http.MethodPost, handle(s, s.count)))
+	mux.Handle("/v1/contains", allow(http.MethodPost, handle(s, s.contains)))
+	mux.Handle("/v1/overlaps", allow(http.MethodPost, handle(s, s.overlaps)))
+	mux.Handle("/v1/divide", allow(http.MethodPost, handle(s, s.divide)))
+	mux.Handle("/v1/set/{operation}", allow(http.MethodPost, handle(s, s.setOperation)))
+	mux.Handle("/v1/utilization", allow(http.MethodPost, handle(s, s.utilization)))
+	mux.Handle("/v1/validate", allow(http.MethodPost, handle(s, s.validate)))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &apiError{status: http.StatusNotFound, code: codeNotFound, err: fmt.Errorf("no such endpoint: %s %s", r.Method, r.URL.Path)})
+	})
+	return mux
+}
+
+// allow rejects requests that do not use the given method.
+func allow(method string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, &apiError{
+				status: http.StatusMethodNotAllowed,
+				code:   codeMethodNotAllowed,
+				err:    fmt.Errorf("method %s is not allowed, use %s", r.Method, method),
+			})
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// handle decodes the JSON request body, calls fn and encodes its response or error.
+func handle[Req, Resp any](s *server, fn func(r *http.Request, req *Req) (*Resp, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if err := s.decode(w, r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := fn(r, &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// decode reads the request body into v, enforcing the size limit and rejecting unknown fields.
+func (s *server) decode(w http.ResponseWriter, r *http.Request, v any) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return &apiError{
+				status: http.StatusUnsupportedMediaType,
+				code:   codeUnsupportedMediaType,
+				err:    fmt.Errorf("unsupported content type: %s", contentType),
+			}
+		}
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.options.MaxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &apiError{
+				status: http.StatusRequestEntityTooLarge,
+				code:   codeRequestTooLarge,
+				err:    fmt.Errorf("request body is larger than %d bytes", maxBytesErr.Limit),
+			}
+		}
+		return badRequest("invalid request body: %s", err)
+	}
+	if decoder.More() {
+		return badRequest("invalid request body: unexpected data after the JSON object")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError reports err with the status and code that belong to it. Errors that are not
+// recognized are reported as internal errors, without exposing their message.
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		apiErr = &apiError{status: http.StatusInternalServerError, code: "internal_error", err: errors.New("internal error")}
+		for _, coreErr := range coreErrors {
+			if errors.Is(err, coreErr.err) {
+				apiErr = &apiError{status: coreErr.status, code: coreErr.code, err: err}
+				break
+			}
+		}
+	}
+	writeJSON(w, apiErr.status, errorResponse{Error: errorBody{Code: apiErr.code, Message: apiErr.Error()}})
+}
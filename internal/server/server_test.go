@@ -0,0 +1,239 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "Explain an IPv4 network",
+			path:     "/v1/explain",
+			body:     `{"network": "10.0.0.0/16"}`,
+			status:   http.StatusOK,
+			expected: `{"network":"10.0.0.0/16","version":4,"baseAddress":"10.0.0.0","netmask":"255.255.0.0","prefixLength":16,"addresses":"65536","hostAddresses":"65534","firstUsableAddress":"10.0.0.1","lastUsableAddress":"10.0.255.254","broadcastAddress":"10.0.255.255"}`,
+		},
+		{
+			name:     "Count the addresses in an IPv6 network",
+			path:     "/v1/count",
+			body:     `{"network": "2001:db8::/32"}`,
+			status:   http.StatusOK,
+			expected: `{"network":"2001:db8::/32","addresses":"79228162514264337593543950336"}`,
+		},
+		{
+			name:     "Contains networks and addresses",
+			path:     "/v1/contains",
+			body:     `{"network": "10.0.0.0/16", "needles": ["10.0.1.0/24", "10.0.0.0/8", "10.1.0.1"]}`,
+			status:   http.StatusOK,
+			expected: `{"results":[{"needle":"10.0.1.0/24","containment":"full"},{"needle":"10.0.0.0/8","containment":"partial"},{"needle":"10.1.0.1","containment":"none"}],"all":false,"any":true}`,
+		},
+		{
+			name:     "Overlapping networks",
+			path:     "/v1/overlaps",
+			body:     `{"networks": ["10.0.0.0/16", "10.1.0.0/16", "10.0.14.0/22"]}`,
+			status:   http.StatusOK,
+			expected: `{"overlaps":true,"pairs":[["10.0.0.0/16","10.0.14.0/22"]]}`,
+		},
+		{
+			name:     "Nested and duplicate networks of both address families",
+			path:     "/v1/overlaps",
+			body:     `{"networks": ["10.0.14.0/24", "2001:db8::/32", "10.0.0.0/8", "192.168.0.0/16", "2001:db8:1::/48", "10.0.14.0/24"]}`,
+			status:   http.StatusOK,
+			expected: `{"overlaps":true,"pairs":[["10.0.14.0/24","10.0.0.0/8"],["10.0.14.0/24","10.0.14.0/24"],["2001:db8::/32","2001:db8:1::/48"],["10.0.0.0/8","10.0.14.0/24"]]}`,
+		},
+		{
+			name:     "Disjoint networks",
+			path:     "/v1/overlaps",
+			body:     `{"networks": ["10.0.0.0/16", "10.1.0.0/16"]}`,
+			status:   http.StatusOK,
+			expected: `{"overlaps":false,"pairs":[]}`,
+		},
+		{
+			name:     "Divide a network",
+			path:     "/v1/divide",
+			body:     `{"network": "10.0.0.0/16", "divisor": 4}`,
+			status:   http.StatusOK,
+			expected: `{"networks":["10.0.0.0/18","10.0.64.0/18","10.0.128.0/18","10.0.192.0/18"]}`,
+		},
+		{
+			name:     "Union of lists",
+			path:     "/v1/set/union",
+			body:     `{"lists": [["10.0.0.0/24", "10.0.1.0/24"], ["10.0.2.0/23"]]}`,
+			status:   http.StatusOK,
+			expected: `{"networks":["10.0.0.0/22"]}`,
+		},
+		{
+			name:     "Difference of lists",
+			path:     "/v1/set/diff",
+			body:     `{"lists": [["10.0.0.0/23"], ["10.0.1.0/24"]]}`,
+			status:   http.StatusOK,
+			expected: `{"networks":["10.0.0.0/24"]}`,
+		},
+		{
+			name:     "Utilization of a network",
+			path:     "/v1/utilization",
+			body:     `{"network": "10.0.0.0/24", "allocations": ["10.0.0.0/25"]}`,
+			status:   http.StatusOK,
+			expected: `{"network":"10.0.0.0/24","addresses":"256","allocated":"128","free":"128","usedPercentage":50,"largestFreeNetwork":"10.0.0.128/25","fragmentationPercentage":0,"freeNetworks":["10.0.0.128/25"]}`,
+		},
+		{
+			name:     "Validate a list with an overlap",
+			path:     "/v1/validate",
+			body:     `{"networks": ["10.0.0.0/16", "10.0.1.0/24"]}`,
+			status:   http.StatusOK,
+			expected: `{"valid":false,"findings":[{"ruleId":"CIDR004","rule":"overlap","severity":"error","source":"request","line":2,"column":1,"message":"10.0.1.0/24 overlaps with 10.0.0.0/16 at request:1"}]}`,
+		},
+		{
+			name:     "Invalid CIDR range",
+			path:     "/v1/explain",
+			body:     `{"network": "10.0.0.0/33"}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"error":{"code":"invalid_cidr","message":"invalid CIDR range: 10.0.0.0/33"}}`,
+		},
+		{
+			name:     "Invalid divisor",
+			path:     "/v1/divide",
+			body:     `{"network": "10.0.0.0/16", "divisor": 0}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"error":{"code":"invalid_divisor","message":"invalid divisor: cannot divide 65536 addresses into 0 divisions"}}`,
+		},
+		{
+			name:     "Allocation outside of the network",
+			path:     "/v1/utilization",
+			body:     `{"network": "10.0.0.0/24", "allocations": ["10.1.0.0/25"]}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"error":{"code":"outside_network","message":"10.1.0.0/25 is outside of the network 10.0.0.0/24"}}`,
+		},
+		{
+			name:     "Too many networks",
+			path:     "/v1/divide",
+			body:     `{"network": "10.0.0.0/8", "divisor": 1000000}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"error":{"code":"too_many_networks","message":"the response would hold more than 65536 networks"}}`,
+		},
+		{
+			name:     "Unknown field",
+			path:     "/v1/count",
+			body:     `{"cidr": "10.0.0.0/16"}`,
+			status:   http.StatusBadRequest,
+			expected: `{"error":{"code":"invalid_request","message":"invalid request body: json: unknown field \"cidr\""}}`,
+		},
+		{
+			name:     "Too few networks",
+			path:     "/v1/overlaps",
+			body:     `{"networks": ["10.0.0.0/16"]}`,
+			status:   http.StatusBadRequest,
+			expected: `{"error":{"code":"invalid_request","message":"provide at least 2 networks"}}`,
+		},
+		{
+			name:     "Unknown set operation",
+			path:     "/v1/set/complement",
+			body:     `{"lists": [["10.0.0.0/16"]]}`,
+			status:   http.StatusNotFound,
+			expected: `{"error":{"code":"not_found","message":"unknown set operation: complement"}}`,
+		},
+		{
+			name:     "Unknown endpoint",
+			path:     "/v1/subtract",
+			body:     `{}`,
+			status:   http.StatusNotFound,
+			expected: `{"error":{"code":"not_found","message":"no such endpoint: POST /v1/subtract"}}`,
+		},
+		{
+			name:     "Wrong method",
+			method:   http.MethodGet,
+			path:     "/v1/explain",
+			status:   http.StatusMethodNotAllowed,
+			expected: `{"error":{"code":"method_not_allowed","message":"method GET is not allowed, use POST"}}`,
+		},
+	}
+
+	handler := server.NewHandler(server.Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.expected != "" {
+				assert.JSONEq(t, tt.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerLimits(t *testing.T) {
+	handler := server.NewHandler(server.Options{MaxRequestBytes: 64})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/overlaps", strings.NewReader(`{"networks": ["10.0.0.0/16", "10.1.0.0/16", "10.2.0.0/16", "10.3.0.0/16"]}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t, `{"error":{"code":"request_too_large","message":"request body is larger than 64 bytes"}}`, rec.Body.String())
+
+	handler = server.NewHandler(server.Options{MaxNetworks: 4})
+	req = httptest.NewRequest(http.MethodPost, "/v1/overlaps", strings.NewReader(`{"networks": ["0.0.0.0/0", "10.0.0.1/32", "10.0.0.2/32", "10.0.0.3/32"]}`))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "Overlapping pairs should count towards the network limit")
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/count", strings.NewReader(`network=10.0.0.0/16`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestHandlerUnmap(t *testing.T) {
+	body := `{"network": "::ffff:10.0.0.0/104", "needles": ["10.1.0.1"]}`
+	for _, unmap := range []bool{false, true} {
+		handler := server.NewHandler(server.Options{Unmap: unmap})
+		req := httptest.NewRequest(http.MethodPost, "/v1/contains", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			All bool `json:"all"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, unmap, resp.All, "An IPv4 address should only be in an IPv4-mapped network when unmapped")
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	handler := server.NewHandler(server.Options{})
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var document struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	for _, path := range []string{"/v1/explain", "/v1/count", "/v1/contains", "/v1/overlaps", "/v1/divide", "/v1/set/{operation}", "/v1/utilization", "/v1/validate"} {
+		assert.Contains(t, document.Paths, path)
+	}
+}
@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"sort"
+	"strings"
+)
+
+// Complete completes the word before the cursor when tab is pressed. The first word of a line
+// completes to a command, the second word to a subcommand, words starting with '$' to variables
+// and all other words to the prefixes used earlier in the session. Its signature matches the
+// AutoCompleteCallback of golang.org/x/term.
+func (s *Session) Complete(line string, pos int, key rune) (newLine string, newPos int, ok bool) {
+	if key != '\t' || pos > len(line) {
+		return "", 0, false
+	}
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	word := line[start:pos]
+	completion, ok := longestCompletion(word, s.candidates(strings.Fields(line[:start]), word))
+	if !ok {
+		return "", 0, false
+	}
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// candidates returns the words that could follow the given preceding words.
+func (s *Session) candidates(preceding []string, word string) []string {
+	var candidates []string
+	switch {
+	case strings.HasPrefix(word, "$"):
+		for _, name := range append(s.Variables(), WorkingSetVariable) {
+			candidates = append(candidates, "$"+name)
+		}
+	case len(preceding) == 0:
+		for name := range s.commands {
+			candidates = append(candidates, name)
+		}
+	default:
+		if len(preceding) == 1 {
+			candidates = append(candidates, s.commands[preceding[0]]...)
+		}
+		for prefix := range s.prefixes {
+			candidates = append(candidates, prefix)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// longestCompletion returns the longest common prefix of the candidates that start with word.
+// A single match is completed with a trailing space.
+func longestCompletion(word string, candidates []string) (string, bool) {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+	if len(matches) == 1 {
+		return matches[0] + " ", true
+	}
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) == len(word) {
+		return "", false
+	}
+	return common, true
+}
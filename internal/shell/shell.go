@@ -0,0 +1,244 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+)
+
+// WorkingSetVariable is the read-only variable that expands to the prefixes in the working set.
+const WorkingSetVariable = "ws"
+
+var (
+	// ErrUnterminatedQuote is returned when a line ends inside a quoted string.
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	// ErrUndefinedVariable is returned when a line refers to a variable that was never assigned.
+	ErrUndefinedVariable = errors.New("undefined variable")
+	// ErrInvalidVariable is returned when a variable name is not valid, or cannot be assigned to.
+	ErrInvalidVariable = errors.New("invalid variable name")
+)
+
+// Session holds the state that is kept between the lines of an interactive session.
+type Session struct {
+	vars       map[string][]string
+	workingSet *core.IPSet
+	prefixes   map[string]struct{}
+	commands   map[string][]string
+}
+
+// NewSession returns an empty session. Commands maps the names that can be completed at
+// the start of a line to the names of their subcommands.
+func NewSession(commands map[string][]string) *Session {
+	return &Session{
+		vars:       make(map[string][]string),
+		workingSet: core.NewIPSet(),
+		prefixes:   make(map[string]struct{}),
+		commands:   commands,
+	}
+}
+
+// Split splits a line into words on whitespace. Single and double quotes group words,
+// and a backslash escapes the next character outside of single quotes.
+func Split(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrUnterminatedQuote
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// ParseAssignment recognizes "$name = value..." and "$name=value..." and returns the name of the
+// variable and its words. The words are not expanded yet.
+func ParseAssignment(words []string) (name string, values []string, ok bool) {
+	if len(words) == 0 || !strings.HasPrefix(words[0], "$") {
+		return "", nil, false
+	}
+	if name, value, found := strings.Cut(words[0][1:], "="); found {
+		if value != "" {
+			values = append(values, value)
+		}
+		return name, append(values, words[1:]...), true
+	}
+	if len(words) >= 2 && words[1] == "=" {
+		return words[0][1:], words[2:], true
+	}
+	if len(words) >= 2 && strings.HasPrefix(words[1], "=") {
+		values = append(values, words[1][1:])
+		return words[0][1:], append(values, words[2:]...), true
+	}
+	return "", nil, false
+}
+
+// Assign sets a variable to the given words, replacing its previous value.
+func (s *Session) Assign(name string, values []string) error {
+	if !isVariableName(name) || name == WorkingSetVariable {
+		return fmt.Errorf("%w: %s", ErrInvalidVariable, name)
+	}
+	s.vars[name] = values
+	s.Remember(values)
+	return nil
+}
+
+// Unset removes a variable.
+func (s *Session) Unset(name string) {
+	delete(s.vars, name)
+}
+
+// Lookup returns the words a variable expands to.
+func (s *Session) Lookup(name string) ([]string, bool) {
+	if name == WorkingSetVariable {
+		return networkStrings(s.workingSet), true
+	}
+	values, ok := s.vars[name]
+	return values, ok
+}
+
+// Variables returns the names of the assigned variables, sorted.
+func (s *Session) Variables() []string {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand replaces the variables in words. A word that consists of a single variable expands
+// to all of its words, a variable inside a word expands to its words separated by commas.
+func (s *Session) Expand(words []string) ([]string, error) {
+	var expanded []string
+	for _, word := range words {
+		if name, ok := strings.CutPrefix(word, "$"); ok && isVariableName(name) {
+			values, ok := s.Lookup(name)
+			if !ok {
+				return nil, fmt.Errorf("%w: $%s", ErrUndefinedVariable, name)
+			}
+			expanded = append(expanded, values...)
+			continue
+		}
+		var err error
+		result := expandVariables(word, func(name string) string {
+			values, ok := s.Lookup(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("%w: $%s", ErrUndefinedVariable, name)
+			}
+			return strings.Join(values, ",")
+		})
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, result)
+	}
+	return expanded, nil
+}
+
+// WorkingSet returns the prefixes that are kept in the working set.
+func (s *Session) WorkingSet() *core.IPSet {
+	return s.workingSet
+}
+
+// SetWorkingSet replaces the prefixes in the working set.
+func (s *Session) SetWorkingSet(set *core.IPSet) {
+	s.workingSet = set
+	s.Remember(networkStrings(set))
+}
+
+// Remember records the words that are prefixes, so they can be completed later on.
+func (s *Session) Remember(words []string) {
+	for _, word := range words {
+		prefix, _, _ := strings.Cut(word, "=")
+		if _, err := core.ParseCIDR(prefix); err == nil {
+			s.prefixes[prefix] = struct{}{}
+		}
+	}
+}
+
+// expandVariables replaces $name and ${name} in s using mapping. A '$' that is not followed by
+// a variable name is kept as is.
+func expandVariables(s string, mapping func(string) string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+		rest := s[i+1:]
+		if strings.HasPrefix(rest, "{") {
+			if end := strings.IndexByte(rest, '}'); end > 0 && isVariableName(rest[1:end]) {
+				b.WriteString(mapping(rest[1:end]))
+				i += end + 1
+				continue
+			}
+		}
+		n := variableNameLength(rest)
+		if n == 0 {
+			b.WriteByte('$')
+			continue
+		}
+		b.WriteString(mapping(rest[:n]))
+		i += n
+	}
+	return b.String()
+}
+
+// variableNameLength returns the length of the variable name that s starts with.
+func variableNameLength(s string) int {
+	for i, r := range s {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
+
+func isVariableName(name string) bool {
+	return name != "" && variableNameLength(name) == len(name)
+}
+
+func networkStrings(set *core.IPSet) []string {
+	networks := set.Networks()
+	values := make([]string, len(networks))
+	for i, network := range networks {
+		values[i] = core.FormatNetwork(network)
+	}
+	return values
+}
@@ -0,0 +1,246 @@
+package shell_test
+
+import (
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/shell"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Split on whitespace",
+			line:     "  divide\t10.0.0.0/16   4 ",
+			expected: []string{"divide", "10.0.0.0/16", "4"},
+		},
+		{
+			name:     "Group quoted words",
+			line:     `map 10.0.0.0/16 "10.0.0.0/20=web servers" '$literal'`,
+			expected: []string{"map", "10.0.0.0/16", "10.0.0.0/20=web servers", "$literal"},
+		},
+		{
+			name:     "Escape a space",
+			line:     `map 10.0.0.0/16 10.0.0.0/20=web\ servers`,
+			expected: []string{"map", "10.0.0.0/16", "10.0.0.0/20=web servers"},
+		},
+		{
+			name:     "Keep an empty quoted word",
+			line:     `explain ""`,
+			expected: []string{"explain", ""},
+		},
+		{
+			name:    "Unterminated quote",
+			line:    `explain "10.0.0.0/16`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := shell.Split(tt.line)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, shell.ErrUnterminatedQuote)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, words)
+		})
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected string
+		values   []string
+		ok       bool
+	}{
+		{
+			name:     "Assignment with spaces",
+			words:    []string{"$vpc", "=", "10.0.0.0/16"},
+			expected: "vpc",
+			values:   []string{"10.0.0.0/16"},
+			ok:       true,
+		},
+		{
+			name:     "Assignment without spaces",
+			words:    []string{"$vpc=10.0.0.0/16", "10.1.0.0/16"},
+			expected: "vpc",
+			values:   []string{"10.0.0.0/16", "10.1.0.0/16"},
+			ok:       true,
+		},
+		{
+			name:     "Assignment with a space after the name",
+			words:    []string{"$vpc", "=10.0.0.0/16"},
+			expected: "vpc",
+			values:   []string{"10.0.0.0/16"},
+			ok:       true,
+		},
+		{
+			name:  "Variable without assignment",
+			words: []string{"$vpc", "10.0.0.0/16"},
+		},
+		{
+			name:  "Command",
+			words: []string{"explain", "$vpc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, values, ok := shell.ParseAssignment(tt.words)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, name)
+			assert.Equal(t, tt.values, values)
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	session := shell.NewSession(nil)
+	assert.NoError(t, session.Assign("vpc", []string{"10.0.0.0/16"}))
+	assert.NoError(t, session.Assign("zones", []string{"10.0.0.0/20", "10.0.16.0/20"}))
+	network, err := core.ParseCIDR("192.168.0.0/24")
+	assert.NoError(t, err)
+	session.SetWorkingSet(core.NewIPSet(network))
+
+	tests := []struct {
+		name     string
+		words    []string
+		expected []string
+		wantErr  error
+	}{
+		{
+			name:     "Expand a variable",
+			words:    []string{"explain", "$vpc"},
+			expected: []string{"explain", "10.0.0.0/16"},
+		},
+		{
+			name:     "Expand a variable to multiple words",
+			words:    []string{"overlaps", "$zones"},
+			expected: []string{"overlaps", "10.0.0.0/20", "10.0.16.0/20"},
+		},
+		{
+			name:     "Expand a variable inside a word",
+			words:    []string{"map", "${vpc}", "$vpc=web", "cost$"},
+			expected: []string{"map", "10.0.0.0/16", "10.0.0.0/16=web", "cost$"},
+		},
+		{
+			name:     "Expand the working set",
+			words:    []string{"count", "$ws"},
+			expected: []string{"count", "192.168.0.0/24"},
+		},
+		{
+			name:    "Undefined variable",
+			words:   []string{"explain", "$subnet"},
+			wantErr: shell.ErrUndefinedVariable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := session.Expand(tt.words)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, words)
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	session := shell.NewSession(nil)
+	assert.NoError(t, session.Assign("vpc", []string{"10.0.0.0/16"}))
+	assert.ErrorIs(t, session.Assign("ws", []string{"10.0.0.0/16"}), shell.ErrInvalidVariable)
+	assert.ErrorIs(t, session.Assign("1st", []string{"10.0.0.0/16"}), shell.ErrInvalidVariable)
+	assert.Equal(t, []string{"vpc"}, session.Variables())
+
+	session.Unset("vpc")
+	_, ok := session.Lookup("vpc")
+	assert.False(t, ok)
+}
+
+func TestComplete(t *testing.T) {
+	session := shell.NewSession(map[string][]string{
+		"explain":  nil,
+		"count":    nil,
+		"contains": nil,
+		"set":      {"union", "intersect"},
+	})
+	assert.NoError(t, session.Assign("vpc", []string{"10.0.0.0/16"}))
+	session.Remember([]string{"explain", "10.0.0.0/20=web", "2001:db8::/32"})
+
+	tests := []struct {
+		name     string
+		line     string
+		pos      int
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "Complete a command",
+			line:     "exp",
+			expected: "explain ",
+			ok:       true,
+		},
+		{
+			name:     "Complete the common prefix of commands",
+			line:     "c",
+			expected: "co",
+			ok:       true,
+		},
+		{
+			name: "Ambiguous command",
+			line: "co",
+		},
+		{
+			name:     "Complete a subcommand",
+			line:     "set u",
+			expected: "set union ",
+			ok:       true,
+		},
+		{
+			name:     "Complete a variable",
+			line:     "explain $v",
+			expected: "explain $vpc ",
+			ok:       true,
+		},
+		{
+			name:     "Complete a prefix used earlier",
+			line:     "count 2001",
+			expected: "count 2001:db8::/32 ",
+			ok:       true,
+		},
+		{
+			name:     "Complete in the middle of a line",
+			line:     "count 10.0 --quiet",
+			pos:      len("count 10.0"),
+			expected: "count 10.0.0.0/ --quiet",
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := tt.pos
+			if pos == 0 {
+				pos = len(tt.line)
+			}
+			line, _, ok := session.Complete(tt.line, pos, '\t')
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, line)
+			}
+		})
+	}
+}
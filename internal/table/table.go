@@ -0,0 +1,101 @@
+// Package table builds and writes tables of networks, such as the subnets that a network is divided in.
+package table
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/helper"
+)
+
+// Formats that a table can be written in.
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// ErrUnknownFormat is returned when a table format does not exist.
+var ErrUnknownFormat = errors.New("unknown table format")
+
+// Table is a header with the rows below it, every row has a cell for each column of the header.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// missing fills the cells of values that a network does not have, such as the broadcast address of a /31.
+const missing = "-"
+
+// Networks returns a table of the networks with their usable range, broadcast address and size, from the
+// same details as explain. IPv6 networks have no broadcast address, so the column is left out for them.
+// Sizes are written with thousands separators if formatNumbers is set, and as plain numbers that
+// spreadsheets can read otherwise.
+func Networks(networks []net.IPNet, formatNumbers bool) *Table {
+	ipv4 := len(networks) > 0 && helper.IsIPv4Network(&networks[0])
+	t := &Table{Header: []string{"Network", "First Usable", "Last Usable"}}
+	if ipv4 {
+		t.Header = append(t.Header, "Broadcast")
+	}
+	t.Header = append(t.Header, "Usable Hosts", "Total")
+
+	for _, network := range networks {
+		details := core.GetNetworkDetails(&network)
+		row := []string{core.FormatNetwork(&network)}
+		if details.UsableAddressRangeHasError {
+			row = append(row, missing, missing)
+		} else {
+			row = append(row, details.FirstUsableIPAddress, details.LastUsableIPAddress)
+		}
+		if ipv4 {
+			if details.BroadcastAddressHasError {
+				row = append(row, missing)
+			} else {
+				row = append(row, details.BroadcastAddress)
+			}
+		}
+		hostCount, count := details.HostCount, details.Count
+		if !formatNumbers {
+			hostCount, count = strings.ReplaceAll(hostCount, ",", ""), strings.ReplaceAll(count, ",", "")
+		}
+		t.Rows = append(t.Rows, append(row, hostCount, count))
+	}
+	return t
+}
+
+// Write writes the table to w in one of the formats text, markdown or csv.
+func (t *Table) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+		for _, row := range append([][]string{t.Header}, t.Rows...) {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case FormatMarkdown:
+		separator := make([]string, len(t.Header))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		for _, row := range append([][]string{t.Header, separator}, t.Rows...) {
+			if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.Header); err != nil {
+			return err
+		}
+		return cw.WriteAll(t.Rows)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,127 @@
+package table_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/bschaatsbergen/cidr/internal/core"
+	"github.com/bschaatsbergen/cidr/internal/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseNetworks(t *testing.T, cidrs ...string) []net.IPNet {
+	t.Helper()
+	networks := make([]net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		network, err := core.ParseCIDR(cidr)
+		require.NoError(t, err)
+		networks = append(networks, *network)
+	}
+	return networks
+}
+
+func TestNetworks(t *testing.T) {
+	tests := []struct {
+		name          string
+		networks      []string
+		formatNumbers bool
+		expected      *table.Table
+	}{
+		{
+			name:          "IPv4 networks",
+			networks:      []string{"10.0.0.0/23", "10.0.2.0/23"},
+			formatNumbers: true,
+			expected: &table.Table{
+				Header: []string{"Network", "First Usable", "Last Usable", "Broadcast", "Usable Hosts", "Total"},
+				Rows: [][]string{
+					{"10.0.0.0/23", "10.0.0.1", "10.0.1.254", "10.0.1.255", "510", "512"},
+					{"10.0.2.0/23", "10.0.2.1", "10.0.3.254", "10.0.3.255", "510", "512"},
+				},
+			},
+		},
+		{
+			name:     "IPv4 networks without a broadcast address",
+			networks: []string{"10.0.0.0/31", "10.0.0.2/32"},
+			expected: &table.Table{
+				Header: []string{"Network", "First Usable", "Last Usable", "Broadcast", "Usable Hosts", "Total"},
+				Rows: [][]string{
+					{"10.0.0.0/31", "10.0.0.0", "10.0.0.1", "-", "2", "2"},
+					{"10.0.0.2/32", "-", "-", "-", "1", "1"},
+				},
+			},
+		},
+		{
+			name:          "IPv6 networks have no broadcast column",
+			networks:      []string{"2001:db8::/33", "2001:db8:8000::/33"},
+			formatNumbers: true,
+			expected: &table.Table{
+				Header: []string{"Network", "First Usable", "Last Usable", "Usable Hosts", "Total"},
+				Rows: [][]string{
+					{"2001:db8::/33", "2001:db8::", "2001:db8:7fff:ffff:ffff:ffff:ffff:ffff", "39,614,081,257,132,168,796,771,975,166", "39,614,081,257,132,168,796,771,975,168"},
+					{"2001:db8:8000::/33", "2001:db8:8000::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "39,614,081,257,132,168,796,771,975,166", "39,614,081,257,132,168,796,771,975,168"},
+				},
+			},
+		},
+		{
+			name:     "Plain numbers",
+			networks: []string{"10.0.0.0/16"},
+			expected: &table.Table{
+				Header: []string{"Network", "First Usable", "Last Usable", "Broadcast", "Usable Hosts", "Total"},
+				Rows: [][]string{
+					{"10.0.0.0/16", "10.0.0.1", "10.0.255.254", "10.0.255.255", "65534", "65536"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, table.Networks(parseNetworks(t, tt.networks...), tt.formatNumbers))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tbl := &table.Table{
+		Header: []string{"Network", "Total"},
+		Rows:   [][]string{{"10.0.0.0/24", "256"}, {"10.0.1.0/24", "256"}},
+	}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: table.FormatText,
+			expected: "Network        Total\n" +
+				"10.0.0.0/24    256\n" +
+				"10.0.1.0/24    256\n",
+		},
+		{
+			format: table.FormatMarkdown,
+			expected: "| Network | Total |\n" +
+				"| --- | --- |\n" +
+				"| 10.0.0.0/24 | 256 |\n" +
+				"| 10.0.1.0/24 | 256 |\n",
+		},
+		{
+			format: table.FormatCSV,
+			expected: "Network,Total\n" +
+				"10.0.0.0/24,256\n" +
+				"10.0.1.0/24,256\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tbl.Write(&buf, tt.format))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+
+	err := tbl.Write(&bytes.Buffer{}, "html")
+	assert.True(t, errors.Is(err, table.ErrUnknownFormat), "expected %v, got %v", table.ErrUnknownFormat, err)
+}